@@ -23,10 +23,14 @@ var (
 	openedSpec C.SDL_AudioSpec
 
 	// Sounds caches the data for all played sounds.
+	// It is only accessed from the main go routine.
 	sounds = map[string]*audioData{}
 
 	// Playing is a slice of all currently-playing sounds.
 	playing []*Sound
+
+	// AudioErr is the error from opening the audio device, if any.
+	audioErr error
 )
 
 func initAudio() {
@@ -35,27 +39,85 @@ func initAudio() {
 	want.format = C.AUDIO_S16LSB
 	want.samples = 8096
 	C.setCallback(&want)
-	C.SDL_OpenAudio(&want, &openedSpec)
+	if C.SDL_OpenAudio(&want, &openedSpec) < 0 {
+		audioErr = sdlError("SDL_OpenAudio")
+		return
+	}
+	audioErr = nil
 	C.SDL_PauseAudio(0)
 }
 
+// CloseAudio closes the audio device and frees all cached sounds.
+// It is called by shutdown after running is cleared, so no function queued
+// by do can use the sounds after they are freed.
+func closeAudio() {
+	if audioErr == nil {
+		C.SDL_CloseAudio()
+	}
+	for _, data := range sounds {
+		C.SDL_FreeWAV((*C.Uint8)(data.data))
+	}
+	sounds = map[string]*audioData{}
+	playing = nil
+}
+
 // PlayWAV plays the sound from a wav file and returns a Sound for it.
+// It may be called from any go routine, including from a function passed to Window.Draw.
+//
+// PlayWAV panics if the sound cannot be loaded.
 func PlayWAV(path string, repeat bool) *Sound {
-	data, ok := sounds[path]
-	if !ok {
-		var err error
-		if data, err = loadWAV(path); err != nil {
-			panic(err)
-		}
-		sounds[path] = data
+	s, err := PlayWAVErr(path, repeat)
+	if err != nil {
+		panic(err)
 	}
-	C.SDL_LockAudio()
-	defer C.SDL_UnlockAudio()
-	s := &Sound{audioData: data, repeat: repeat}
-	playing = append(playing, s)
 	return s
 }
 
+// PlayWAVErr is like PlayWAV, but it returns an error if the sound cannot be loaded,
+// or ErrNotRunning if the user interface is not running.
+func PlayWAVErr(path string, repeat bool) (*Sound, error) {
+	var s *Sound
+	err := doErr(func() error {
+		data, err := getSound(path)
+		if err != nil {
+			return err
+		}
+		C.SDL_LockAudio()
+		defer C.SDL_UnlockAudio()
+		s = &Sound{audioData: data, repeat: repeat}
+		playing = append(playing, s)
+		return nil
+	})
+	return s, err
+}
+
+// LoadSound loads a wav file, caching it for later use by PlayWAV.
+// Errors loading the file are reported as a *LoadError.
+// It returns ErrNotRunning if the user interface is not running.
+func LoadSound(path string) error {
+	return doErr(func() error {
+		_, err := getSound(path)
+		return err
+	})
+}
+
+// GetSound returns the sound loaded from path, loading it if it is not cached.
+// It must be called from the main go routine.
+func getSound(path string) (*audioData, error) {
+	if data, ok := sounds[path]; ok {
+		return data, nil
+	}
+	if audioErr != nil {
+		return nil, audioErr
+	}
+	data, err := loadWAV(path)
+	if err != nil {
+		return nil, &LoadError{Path: path, Err: err}
+	}
+	sounds[path] = data
+	return data, nil
+}
+
 // A Sound is a stream of currently playing audio.
 type Sound struct {
 	*audioData
@@ -64,11 +126,15 @@ type Sound struct {
 }
 
 // Stop stops the sound from playing.
+// It may be called from any go routine, including from a function passed to Window.Draw.
+// After the user interface has shut down, all sounds are stopped, and Stop has no effect.
 func (s *Sound) Stop() {
-	C.SDL_LockAudio()
-	defer C.SDL_UnlockAudio()
-	s.repeat = false
-	s.pos = 0
+	do(func() {
+		C.SDL_LockAudio()
+		defer C.SDL_UnlockAudio()
+		s.repeat = false
+		s.pos = 0
+	})
 }
 
 func (s *Sound) done() bool {
@@ -120,7 +186,7 @@ func loadWAV(path string) (*audioData, error) {
 	var s C.SDL_AudioSpec // Apparently just a buffer?  SDL just zeroes it, fills it, and returns it.
 	spec := C.SDL_LoadWAV_RW(C.SDL_RWFromFile(cpath, rb), 1, &s, &data, &len)
 	if spec == nil {
-		return nil, sdlError()
+		return nil, sdlError("SDL_LoadWAV_RW")
 	}
 
 	var err error
@@ -142,19 +208,22 @@ func convert(s *C.SDL_AudioSpec, data *C.Uint8, len C.Uint32) (*C.Uint8, C.Uint3
 	o := openedSpec
 	switch C.SDL_BuildAudioCVT(&cvt, s.format, s.channels, s.freq, o.format, o.channels, o.freq) {
 	case -1:
+		C.SDL_FreeWAV(data)
 		return nil, 0, errors.New("Cannot convert audio")
 	case 0:
 		return data, len, nil
 	}
 
-	buf := C.malloc(C.size_t(len) * C.size_t(cvt.len_mult))
+	// Allocate with SDL_malloc so that all sound data can be freed with SDL_FreeWAV.
+	buf := C.SDL_malloc(C.size_t(len) * C.size_t(cvt.len_mult))
 	cvt.buf = (*C.Uint8)(buf)
 	cvt.len = C.int(len)
 	C.memcpy(buf, unsafe.Pointer(data), C.size_t(len))
-	C.free(unsafe.Pointer(data))
+	C.SDL_FreeWAV(data)
 
 	if C.SDL_ConvertAudio(&cvt) < 0 {
-		return nil, 0, sdlError()
+		C.SDL_free(buf)
+		return nil, 0, sdlError("SDL_ConvertAudio")
 	}
 	return cvt.buf, C.Uint32(cvt.len), nil
 }
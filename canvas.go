@@ -10,6 +10,7 @@ import "C"
 import (
 	"image"
 	"image/color"
+	"image/draw"
 	"image/png"
 	"os"
 	"unsafe"
@@ -25,7 +26,7 @@ type Canvas struct {
 // Clear clears the canvas with the drawing color.
 func (c Canvas) Clear() {
 	if C.SDL_RenderClear(c.win.rend) < 0 {
-		panic(sdlError())
+		panic(sdlError("SDL_RenderClear"))
 	}
 }
 
@@ -38,7 +39,7 @@ func (c Canvas) SetColor(col color.Color) {
 	b8 := C.Uint8(float64(b) * f)
 	a8 := C.Uint8(float64(a) * f)
 	if C.SDL_SetRenderDrawColor(c.win.rend, r8, g8, b8, a8) < 0 {
-		panic(sdlError())
+		panic(sdlError("SDL_SetRenderDrawColor"))
 	}
 }
 
@@ -46,22 +47,31 @@ func (c Canvas) SetColor(col color.Color) {
 func (c Canvas) color() color.Color {
 	var r, g, b, a C.Uint8
 	if C.SDL_GetRenderDrawColor(c.win.rend, &r, &g, &b, &a) < 0 {
-		panic(sdlError())
+		panic(sdlError("SDL_GetRenderDrawColor"))
 	}
 	return color.NRGBA{uint8(r), uint8(g), uint8(b), uint8(a)}
 }
 
+// Size returns the width and height of the canvas in pixels.
+func (c Canvas) Size() (width, height int) {
+	var w, h C.int
+	if C.SDL_GetRendererOutputSize(c.win.rend, &w, &h) < 0 {
+		panic(sdlError("SDL_GetRendererOutputSize"))
+	}
+	return int(w), int(h)
+}
+
 // DrawPoints draws multiple points on the canvas.
 func (c Canvas) DrawPoints(points ...image.Point) {
 	if C.SDL_RenderDrawPoints(c.win.rend, sdlPoints(points), C.int(len(points))) < 0 {
-		panic(sdlError())
+		panic(sdlError("SDL_RenderDrawPoints"))
 	}
 }
 
 // DrawLines draws a series of connected lines on the canvas.
 func (c Canvas) DrawLines(points ...image.Point) {
 	if C.SDL_RenderDrawLines(c.win.rend, sdlPoints(points), C.int(len(points))) < 0 {
-		panic(sdlError())
+		panic(sdlError("SDL_RenderDrawLines"))
 	}
 }
 
@@ -77,14 +87,14 @@ func sdlPoints(points []image.Point) *C.SDL_Point {
 // DrawRects draws some number of rectangles on the canvas.
 func (c Canvas) DrawRects(rects ...image.Rectangle) {
 	if C.SDL_RenderDrawRects(c.win.rend, sdlRects(rects), C.int(len(rects))) < 0 {
-		panic(sdlError())
+		panic(sdlError("SDL_RenderDrawRects"))
 	}
 }
 
 // FillRects fills some number of rectangles on the canvas with the drawing color.
 func (c Canvas) FillRects(rects ...image.Rectangle) {
 	if C.SDL_RenderFillRects(c.win.rend, sdlRects(rects), C.int(len(rects))) < 0 {
-		panic(sdlError())
+		panic(sdlError("SDL_RenderFillRects"))
 	}
 }
 
@@ -110,12 +120,28 @@ func sdlRect(rect *image.Rectangle) *C.SDL_Rect {
 
 // DrawPNG draws the image loaded from a PNG file to the canvas.
 // The image is drawn with the upper-left corner located at x, y.
+//
+// DrawPNG panics if the image cannot be loaded or drawn.
 func (c Canvas) DrawPNG(path string, x, y int) {
+	if err := c.DrawPNGErr(path, x, y); err != nil {
+		panic(err)
+	}
+}
+
+// DrawPNGErr is like DrawPNG, but it returns an error if the image cannot be loaded or drawn.
+func (c Canvas) DrawPNGErr(path string, x, y int) error {
 	tex, ok := c.win.imgs[path]
 	if !ok {
-		img := loadPNG(path)
+		img, err := LoadImage(path)
+		if err != nil {
+			return err
+		}
+		t, err := texFromImage(c.win.rend, img)
+		if err != nil {
+			return err
+		}
 		tex = texture{
-			tex:    texFromImage(c.win.rend, img),
+			tex:    t,
 			width:  img.Bounds().Dx(),
 			height: img.Bounds().Dy(),
 		}
@@ -123,29 +149,68 @@ func (c Canvas) DrawPNG(path string, x, y int) {
 	}
 	dst := image.Rect(x, y, x+tex.width, y+tex.height)
 	if C.SDL_RenderCopy(c.win.rend, tex.tex, nil, sdlRect(&dst)) < 0 {
-		panic(sdlError())
+		return sdlError("SDL_RenderCopy")
 	}
+	return nil
 }
 
 // SetFont sets the current font face and size (in points).
+//
+// SetFont panics if the font cannot be loaded.
 func (c *Canvas) SetFont(path string, size int) {
-	c.font = getFont(path, size)
+	if err := c.SetFontErr(path, size); err != nil {
+		panic(err)
+	}
+}
+
+// SetFontErr is like SetFont, but it returns an error if the font cannot be loaded.
+// On error, the current font is unchanged.
+func (c *Canvas) SetFontErr(path string, size int) error {
+	f, err := getFont(path, size)
+	if err != nil {
+		return err
+	}
+	c.font = f
+	return nil
 }
 
 // FillString fills a string of text in the current font and draw color.  X and y specify the
 // upper-left corner of the bounding box of the text, and the width and height of the
 // bounding box is returned.
+//
+// FillString panics if the text cannot be drawn.
 func (c Canvas) FillString(s string, x, y int) (width, height int) {
-	img := c.font.draw(s, c.color())
-	tex := texFromImage(c.win.rend, img)
-	defer C.SDL_DestroyTexture(tex)
+	width, height, err := c.FillStringErr(s, x, y)
+	if err != nil {
+		panic(err)
+	}
+	return width, height
+}
+
+// FillStringErr is like FillString, but it returns an error if the text cannot be drawn.
+func (c Canvas) FillStringErr(s string, x, y int) (width, height int, err error) {
+	if c.font.Font == nil {
+		return 0, 0, ErrNoFont
+	}
+	img, err := c.font.draw(s, c.color())
+	if err != nil {
+		return 0, 0, err
+	}
 	b := img.Bounds()
 	w, h := b.Dx(), b.Dy()
+	if b.Empty() {
+		return w, h, nil
+	}
+	tex, err := texFromImage(c.win.rend, img)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer C.SDL_DestroyTexture(tex)
 	dst := image.Rect(x, y, x+w, y+h)
 	if C.SDL_RenderCopy(c.win.rend, tex, nil, sdlRect(&dst)) < 0 {
-		panic(sdlError())
+		return 0, 0, sdlError("SDL_RenderCopy")
 	}
-	return w, h
+	return w, h, nil
 }
 
 // StringSize returns the width and height of the string in pixels when rendered in the current font.
@@ -154,34 +219,89 @@ func (c Canvas) StringSize(s string) (width, height int) {
 	return c.font.width(s), height
 }
 
-func texFromImage(rend *C.SDL_Renderer, img *image.NRGBA) *C.SDL_Texture {
+// ReadPixels returns the pixels drawn so far within a rectangle of the canvas.
+// If the rectangle is empty, the pixels of the entire canvas are returned.
+// The returned image's bounds are the rectangle, clipped to the canvas.
+//
+// ReadPixels must be called before the drawing function passed to Window.Draw
+// returns, since the window's contents are undefined after they are presented.
+// It is slow, and intended for screenshots and tests, not for use every frame.
+func (c Canvas) ReadPixels(r image.Rectangle) (*image.NRGBA, error) {
+	var w, h C.int
+	if C.SDL_GetRendererOutputSize(c.win.rend, &w, &h) < 0 {
+		return nil, sdlError("SDL_GetRendererOutputSize")
+	}
+	bounds := image.Rect(0, 0, int(w), int(h))
+	if !r.Empty() {
+		bounds = r.Intersect(bounds)
+	}
+	img := image.NewNRGBA(bounds)
+	if bounds.Empty() {
+		return img, nil
+	}
+	fmt := C.SDL_PIXELFORMAT_ABGR8888
+	if C.SDL_RenderReadPixels(c.win.rend, sdlRect(&bounds), C.Uint32(fmt), unsafe.Pointer(&img.Pix[0]), C.int(img.Stride)) < 0 {
+		return nil, sdlError("SDL_RenderReadPixels")
+	}
+	return img, nil
+}
+
+func texFromImage(rend *C.SDL_Renderer, img *image.NRGBA) (*C.SDL_Texture, error) {
 	b := img.Bounds()
 	w, h := b.Dx(), b.Dy()
 	fmt := C.SDL_PIXELFORMAT_ABGR8888
 	acc := C.SDL_TEXTUREACCESS_STATIC
 	tex := C.SDL_CreateTexture(rend, C.Uint32(fmt), C.int(acc), C.int(w), C.int(h))
 	if tex == nil {
-		panic(sdlError())
+		return nil, sdlError("SDL_CreateTexture")
 	}
 	if C.SDL_UpdateTexture(tex, nil, unsafe.Pointer(&img.Pix[0]), C.int(img.Stride)) < 0 {
-		panic(sdlError())
+		C.SDL_DestroyTexture(tex)
+		return nil, sdlError("SDL_UpdateTexture")
 	}
 	if C.SDL_SetTextureBlendMode(tex, C.SDL_BLENDMODE_BLEND) < 0 {
-		panic(sdlError())
+		C.SDL_DestroyTexture(tex)
+		return nil, sdlError("SDL_SetTextureBlendMode")
 	}
-	return tex
+	return tex, nil
 }
 
-func loadPNG(path string) *image.NRGBA {
+// LoadImage loads an image from a PNG file.
+// Errors are reported as a *LoadError.
+func LoadImage(path string) (*image.NRGBA, error) {
 	r, err := os.Open(path)
 	if err != nil {
-		panic(err)
+		return nil, &LoadError{Path: path, Err: err}
 	}
 	defer r.Close()
 
 	img, err := png.Decode(r)
 	if err != nil {
-		panic(err)
+		return nil, &LoadError{Path: path, Err: err}
+	}
+	return toNRGBA(img), nil
+}
+
+// ToNRGBA returns img as an *image.NRGBA, converting it if necessary.
+func toNRGBA(img image.Image) *image.NRGBA {
+	if nrgba, ok := img.(*image.NRGBA); ok {
+		return nrgba
+	}
+	b := img.Bounds()
+	nrgba := image.NewNRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+	draw.Draw(nrgba, nrgba.Bounds(), img, b.Min, draw.Src)
+	return nrgba
+}
+
+// SavePNG saves an image to a PNG file, such as an image returned by Canvas.ReadPixels.
+func SavePNG(path string, img image.Image) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := png.Encode(f, img); err != nil {
+		f.Close()
+		return err
 	}
-	return img.(*image.NRGBA)
+	return f.Close()
 }
@@ -0,0 +1,57 @@
+// © 2013 the Ui Authors under the MIT license. See AUTHORS for the list of authors.
+
+package ui_test
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/velour/ui"
+	"github.com/velour/ui/uitest"
+)
+
+// The golden images are in testdata; regenerate them with:
+//
+//	go test -run Golden -uitest.update
+//
+// Lines, rectangle outlines, and text are rasterized differently by different
+// versions of SDL and SDL_ttf, so they are checked pixel by pixel in ui_test.go.
+
+func TestGoldenDrawPoints(t *testing.T) {
+	uitest.CheckDraw(t, "points", 16, 16, 0, func(c ui.Canvas) {
+		c.SetColor(white)
+		c.DrawPoints(image.Pt(0, 0), image.Pt(3, 5), image.Pt(8, 8), image.Pt(15, 15))
+		c.SetColor(red)
+		c.DrawPoints(image.Pt(15, 0), image.Pt(0, 15))
+	})
+}
+
+func TestGoldenFillRects(t *testing.T) {
+	uitest.CheckDraw(t, "fillrects", 16, 16, 0, func(c ui.Canvas) {
+		c.SetColor(white)
+		c.FillRects(image.Rect(1, 1, 7, 7), image.Rect(9, 9, 15, 15))
+		c.SetColor(red)
+		c.FillRects(image.Rect(9, 1, 15, 7))
+	})
+}
+
+func TestGoldenDrawPNG(t *testing.T) {
+	uitest.CheckDraw(t, "png", 64, 64, 1, func(c ui.Canvas) {
+		// Draw the gopher's nose, clipped by the canvas.
+		if err := c.DrawPNGErr(gopherPNG, -180, -90); err != nil {
+			t.Errorf("DrawPNGErr: %v", err)
+		}
+	})
+}
+
+func TestGoldenAlphaBlend(t *testing.T) {
+	uitest.CheckDraw(t, "alpha", 16, 16, 1, func(c ui.Canvas) {
+		c.SetColor(red)
+		c.FillRects(image.Rect(0, 0, 10, 10))
+		c.SetColor(color.NRGBA{0, 0, 255, 128})
+		c.FillRects(image.Rect(6, 6, 16, 16))
+		c.SetColor(color.NRGBA{255, 255, 255, 64})
+		c.FillRects(image.Rect(0, 12, 4, 16))
+	})
+}
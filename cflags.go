@@ -2,6 +2,8 @@
 
 package ui
 
+// Ui requires SDL 2.0.6 or later; see README.md for features that need newer versions.
+
 /*
 #cgo darwin CFLAGS: -I darwin
 #cgo darwin LDFLAGS: -framework SDL2
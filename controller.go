@@ -0,0 +1,511 @@
+// © 2013 the Ui Authors under the MIT license. See AUTHORS for the list of authors.
+
+package ui
+
+/*
+#include "ui.h"
+
+// Virtual joysticks were added in SDL 2.0.14.  With older versions,
+// these functions fail, so that virtual controllers cannot be attached.
+#if SDL_VERSION_ATLEAST(2, 0, 14)
+static int attachVirtualController(void) {
+	return SDL_JoystickAttachVirtual(SDL_JOYSTICK_TYPE_GAMECONTROLLER,
+		SDL_CONTROLLER_AXIS_MAX, SDL_CONTROLLER_BUTTON_MAX, 0);
+}
+static int detachVirtual(int index) {
+	return SDL_JoystickDetachVirtual(index);
+}
+static int setVirtualButton(SDL_Joystick *joy, int button, Uint8 state) {
+	return SDL_JoystickSetVirtualButton(joy, button, state);
+}
+static int setVirtualAxis(SDL_Joystick *joy, int axis, Sint16 value) {
+	return SDL_JoystickSetVirtualAxis(joy, axis, value);
+}
+#else
+static int attachVirtualController(void) {
+	return SDL_SetError("virtual controllers require SDL 2.0.14 or later");
+}
+static int detachVirtual(int index) {
+	return SDL_SetError("virtual controllers require SDL 2.0.14 or later");
+}
+static int setVirtualButton(SDL_Joystick *joy, int button, Uint8 state) {
+	return SDL_SetError("virtual controllers require SDL 2.0.14 or later");
+}
+static int setVirtualAxis(SDL_Joystick *joy, int axis, Sint16 value) {
+	return SDL_SetError("virtual controllers require SDL 2.0.14 or later");
+}
+#endif
+*/
+import "C"
+
+import (
+	"fmt"
+	"strconv"
+	"unsafe"
+)
+
+// A ControllerButton is a button of a game controller,
+// named after the buttons of an Xbox controller.
+type ControllerButton C.int
+
+// The game controller buttons.
+const (
+	ControllerButtonA             ControllerButton = C.SDL_CONTROLLER_BUTTON_A
+	ControllerButtonB             ControllerButton = C.SDL_CONTROLLER_BUTTON_B
+	ControllerButtonX             ControllerButton = C.SDL_CONTROLLER_BUTTON_X
+	ControllerButtonY             ControllerButton = C.SDL_CONTROLLER_BUTTON_Y
+	ControllerButtonBack          ControllerButton = C.SDL_CONTROLLER_BUTTON_BACK
+	ControllerButtonGuide         ControllerButton = C.SDL_CONTROLLER_BUTTON_GUIDE
+	ControllerButtonStart         ControllerButton = C.SDL_CONTROLLER_BUTTON_START
+	ControllerButtonLeftStick     ControllerButton = C.SDL_CONTROLLER_BUTTON_LEFTSTICK
+	ControllerButtonRightStick    ControllerButton = C.SDL_CONTROLLER_BUTTON_RIGHTSTICK
+	ControllerButtonLeftShoulder  ControllerButton = C.SDL_CONTROLLER_BUTTON_LEFTSHOULDER
+	ControllerButtonRightShoulder ControllerButton = C.SDL_CONTROLLER_BUTTON_RIGHTSHOULDER
+	ControllerButtonDPadUp        ControllerButton = C.SDL_CONTROLLER_BUTTON_DPAD_UP
+	ControllerButtonDPadDown      ControllerButton = C.SDL_CONTROLLER_BUTTON_DPAD_DOWN
+	ControllerButtonDPadLeft      ControllerButton = C.SDL_CONTROLLER_BUTTON_DPAD_LEFT
+	ControllerButtonDPadRight     ControllerButton = C.SDL_CONTROLLER_BUTTON_DPAD_RIGHT
+)
+
+var controllerButtonNames = map[ControllerButton]string{
+	ControllerButtonA:             "ControllerButtonA",
+	ControllerButtonB:             "ControllerButtonB",
+	ControllerButtonX:             "ControllerButtonX",
+	ControllerButtonY:             "ControllerButtonY",
+	ControllerButtonBack:          "ControllerButtonBack",
+	ControllerButtonGuide:         "ControllerButtonGuide",
+	ControllerButtonStart:         "ControllerButtonStart",
+	ControllerButtonLeftStick:     "ControllerButtonLeftStick",
+	ControllerButtonRightStick:    "ControllerButtonRightStick",
+	ControllerButtonLeftShoulder:  "ControllerButtonLeftShoulder",
+	ControllerButtonRightShoulder: "ControllerButtonRightShoulder",
+	ControllerButtonDPadUp:        "ControllerButtonDPadUp",
+	ControllerButtonDPadDown:      "ControllerButtonDPadDown",
+	ControllerButtonDPadLeft:      "ControllerButtonDPadLeft",
+	ControllerButtonDPadRight:     "ControllerButtonDPadRight",
+}
+
+func (b ControllerButton) String() string {
+	if n, ok := controllerButtonNames[b]; ok {
+		return n
+	}
+	return "Unknown(" + strconv.Itoa(int(b)) + ")"
+}
+
+// Name returns SDL's name for the button, as used in controller mappings, such as "a" or "dpup".
+func (b ControllerButton) Name() string {
+	return C.GoString(C.SDL_GameControllerGetStringForButton(C.SDL_GameControllerButton(b)))
+}
+
+// ParseControllerButton returns the button with the given name, as returned by ControllerButton.Name.
+func ParseControllerButton(name string) (ControllerButton, error) {
+	cname := C.CString(name)
+	defer C.free(unsafe.Pointer(cname))
+	b := C.SDL_GameControllerGetButtonFromString(cname)
+	if b == C.SDL_CONTROLLER_BUTTON_INVALID {
+		return 0, &ParseError{Kind: "controller button", Text: name}
+	}
+	return ControllerButton(b), nil
+}
+
+// A ControllerAxis is an axis of a game controller.
+// Stick axes range from -32768 to 32767, and trigger axes range from 0 to 32767.
+type ControllerAxis C.int
+
+// The game controller axes.
+const (
+	ControllerAxisLeftX        ControllerAxis = C.SDL_CONTROLLER_AXIS_LEFTX
+	ControllerAxisLeftY        ControllerAxis = C.SDL_CONTROLLER_AXIS_LEFTY
+	ControllerAxisRightX       ControllerAxis = C.SDL_CONTROLLER_AXIS_RIGHTX
+	ControllerAxisRightY       ControllerAxis = C.SDL_CONTROLLER_AXIS_RIGHTY
+	ControllerAxisTriggerLeft  ControllerAxis = C.SDL_CONTROLLER_AXIS_TRIGGERLEFT
+	ControllerAxisTriggerRight ControllerAxis = C.SDL_CONTROLLER_AXIS_TRIGGERRIGHT
+)
+
+var controllerAxisNames = map[ControllerAxis]string{
+	ControllerAxisLeftX:        "ControllerAxisLeftX",
+	ControllerAxisLeftY:        "ControllerAxisLeftY",
+	ControllerAxisRightX:       "ControllerAxisRightX",
+	ControllerAxisRightY:       "ControllerAxisRightY",
+	ControllerAxisTriggerLeft:  "ControllerAxisTriggerLeft",
+	ControllerAxisTriggerRight: "ControllerAxisTriggerRight",
+}
+
+func (a ControllerAxis) String() string {
+	if n, ok := controllerAxisNames[a]; ok {
+		return n
+	}
+	return "Unknown(" + strconv.Itoa(int(a)) + ")"
+}
+
+// A ControllerDeviceKind is the kind of a ControllerDeviceEvent.
+type ControllerDeviceKind C.Uint32
+
+const (
+	// ControllerAdded says that a controller has been connected.
+	ControllerAdded ControllerDeviceKind = C.SDL_CONTROLLERDEVICEADDED
+
+	// ControllerRemoved says that an open controller has been disconnected.
+	ControllerRemoved ControllerDeviceKind = C.SDL_CONTROLLERDEVICEREMOVED
+
+	// ControllerRemapped says that the mapping of an open controller has changed.
+	ControllerRemapped ControllerDeviceKind = C.SDL_CONTROLLERDEVICEREMAPPED
+)
+
+var controllerDeviceKindNames = map[ControllerDeviceKind]string{
+	ControllerAdded:    "ControllerAdded",
+	ControllerRemoved:  "ControllerRemoved",
+	ControllerRemapped: "ControllerRemapped",
+}
+
+func (k ControllerDeviceKind) String() string {
+	if n, ok := controllerDeviceKindNames[k]; ok {
+		return n
+	}
+	return "Unknown(" + strconv.Itoa(int(k)) + ")"
+}
+
+// A ControllerDeviceEvent says that a game controller has been connected,
+// disconnected or remapped.  It is an application event, delivered on the
+// channel returned by Events.
+type ControllerDeviceEvent struct {
+	eventHeader
+	Event ControllerDeviceKind
+
+	// Index is the device index of an added controller, for use with OpenController.
+	// It is -1 for other kinds of events.
+	Index int
+
+	// ID is the instance ID of the controller, as returned by Controller.ID.
+	ID int
+}
+
+func newControllerDeviceEvent(ev *C.SDL_Event) *ControllerDeviceEvent {
+	e := (*C.SDL_ControllerDeviceEvent)(unsafe.Pointer(ev))
+	d := &ControllerDeviceEvent{
+		eventHeader: newEventHeader(0, e.timestamp),
+		Event:       ControllerDeviceKind(e._type),
+		Index:       -1,
+		ID:          int(e.which),
+	}
+	if d.Event == ControllerAdded {
+		// For added devices, SDL reports the device index, not the instance ID.
+		d.Index = int(e.which)
+		d.ID = int(C.SDL_JoystickGetDeviceInstanceID(C.int(e.which)))
+	}
+	return d
+}
+
+func (e *ControllerDeviceEvent) appEvent() {}
+
+func (e *ControllerDeviceEvent) String() string {
+	return fmt.Sprintf("ControllerDeviceEvent{Event: %v, Index: %d, ID: %d}", e.Event, e.Index, e.ID)
+}
+
+// A ControllerButtonEvent is a press or release of a button on an open game controller.
+// It is an application event, delivered on the channel returned by Events.
+type ControllerButtonEvent struct {
+	eventHeader
+
+	// ID is the instance ID of the controller, as returned by Controller.ID.
+	ID     int
+	Button ControllerButton
+
+	// Down is true if the button was pressed, and false if it was released.
+	Down bool
+}
+
+func newControllerButtonEvent(ev *C.SDL_Event) *ControllerButtonEvent {
+	e := (*C.SDL_ControllerButtonEvent)(unsafe.Pointer(ev))
+	return &ControllerButtonEvent{
+		eventHeader: newEventHeader(0, e.timestamp),
+		ID:          int(e.which),
+		Button:      ControllerButton(e.button),
+		Down:        e.state == C.SDL_PRESSED,
+	}
+}
+
+func (e *ControllerButtonEvent) appEvent() {}
+
+func (e *ControllerButtonEvent) String() string {
+	return fmt.Sprintf("ControllerButtonEvent{ID: %d, Button: %v, Down: %t}", e.ID, e.Button, e.Down)
+}
+
+// A ControllerAxisEvent is a movement of an axis of an open game controller.
+// It is an application event, delivered on the channel returned by Events.
+type ControllerAxisEvent struct {
+	eventHeader
+
+	// ID is the instance ID of the controller, as returned by Controller.ID.
+	ID   int
+	Axis ControllerAxis
+
+	// Value is the new value of the axis.
+	Value int
+}
+
+func newControllerAxisEvent(ev *C.SDL_Event) *ControllerAxisEvent {
+	e := (*C.SDL_ControllerAxisEvent)(unsafe.Pointer(ev))
+	return &ControllerAxisEvent{
+		eventHeader: newEventHeader(0, e.timestamp),
+		ID:          int(e.which),
+		Axis:        ControllerAxis(e.axis),
+		Value:       int(e.value),
+	}
+}
+
+func (e *ControllerAxisEvent) appEvent() {}
+
+func (e *ControllerAxisEvent) String() string {
+	return fmt.Sprintf("ControllerAxisEvent{ID: %d, Axis: %v, Value: %d}", e.ID, e.Axis, e.Value)
+}
+
+// ControllerIndexes returns the device indexes of the connected joysticks
+// that are supported as game controllers.
+func ControllerIndexes() []int {
+	var indexes []int
+	do(func() {
+		n := int(C.SDL_NumJoysticks())
+		for i := 0; i < n; i++ {
+			if C.SDL_IsGameController(C.int(i)) == C.SDL_TRUE {
+				indexes = append(indexes, i)
+			}
+		}
+	})
+	return indexes
+}
+
+// LoadControllerMappings adds game controller mappings from a file in the
+// format of the SDL_GameControllerDB database, and returns the number of
+// mappings added.
+func LoadControllerMappings(path string) (int, error) {
+	var n int
+	err := doErr(func() error {
+		cpath := C.CString(path)
+		defer C.free(unsafe.Pointer(cpath))
+		rw := C.SDL_RWFromFile(cpath, rb)
+		if rw == nil {
+			return &LoadError{Path: path, Err: sdlError("SDL_RWFromFile")}
+		}
+		if n = int(C.SDL_GameControllerAddMappingsFromRW(rw, 1)); n < 0 {
+			return &LoadError{Path: path, Err: sdlError("SDL_GameControllerAddMappingsFromRW")}
+		}
+		return nil
+	})
+	return n, err
+}
+
+// AddControllerMapping adds a single game controller mapping,
+// in the format of the SDL_GameControllerDB database.
+func AddControllerMapping(mapping string) error {
+	return doErr(func() error {
+		cmapping := C.CString(mapping)
+		defer C.free(unsafe.Pointer(cmapping))
+		if C.SDL_GameControllerAddMapping(cmapping) < 0 {
+			return sdlError("SDL_GameControllerAddMapping")
+		}
+		return nil
+	})
+}
+
+// A Controller is an open game controller.
+// Events for a controller are only delivered while it is open.
+type Controller struct {
+	gc *C.SDL_GameController
+	id int
+}
+
+// OpenController opens the game controller with the given device index,
+// such as from ControllerIndexes or a ControllerDeviceEvent.
+func OpenController(index int) (*Controller, error) {
+	c := &Controller{}
+	err := doErr(func() error {
+		if c.gc = C.SDL_GameControllerOpen(C.int(index)); c.gc == nil {
+			return sdlError("SDL_GameControllerOpen")
+		}
+		c.id = int(C.SDL_JoystickInstanceID(C.SDL_GameControllerGetJoystick(c.gc)))
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// Do is like doErr, but it returns ErrControllerClosed without calling f
+// if the controller has been closed.
+func (c *Controller) do(f func() error) error {
+	return doErr(func() error {
+		if c.gc == nil {
+			return ErrControllerClosed
+		}
+		return f()
+	})
+}
+
+// ID returns the controller's instance ID, which identifies it in events.
+func (c *Controller) ID() int {
+	return c.id
+}
+
+// Name returns the controller's name.
+func (c *Controller) Name() string {
+	var name string
+	c.do(func() error {
+		name = C.GoString(C.SDL_GameControllerName(c.gc))
+		return nil
+	})
+	return name
+}
+
+// Button returns whether the button is down.
+func (c *Controller) Button(b ControllerButton) bool {
+	var down bool
+	c.do(func() error {
+		down = C.SDL_GameControllerGetButton(c.gc, C.SDL_GameControllerButton(b)) != 0
+		return nil
+	})
+	return down
+}
+
+// Axis returns the value of the axis.
+func (c *Controller) Axis(a ControllerAxis) int {
+	var v int
+	c.do(func() error {
+		v = int(C.SDL_GameControllerGetAxis(c.gc, C.SDL_GameControllerAxis(a)))
+		return nil
+	})
+	return v
+}
+
+// Close closes the controller.  Closing a closed controller has no effect.
+func (c *Controller) Close() error {
+	err := doErr(func() error {
+		if c.gc != nil {
+			C.SDL_GameControllerClose(c.gc)
+			c.gc = nil
+		}
+		return nil
+	})
+	if err == ErrNotRunning {
+		// SDL_Quit closed the controller.
+		return nil
+	}
+	return err
+}
+
+// A VirtualController is a simulated game controller, for testing without hardware.
+// Once attached, it appears as a connected controller, and its buttons and axes
+// are set by its methods.  It requires SDL 2.0.14 or later; with older versions,
+// AttachVirtualController returns an error.
+type VirtualController struct {
+	index int
+	joy   *C.SDL_Joystick
+}
+
+// VirtualControllerMapping maps the virtual controller's joystick buttons and axes
+// to the ControllerButton and ControllerAxis of the same number.
+const virtualControllerMapping = "Virtual Controller," +
+	"a:b0,b:b1,x:b2,y:b3,back:b4,guide:b5,start:b6,leftstick:b7,rightstick:b8," +
+	"leftshoulder:b9,rightshoulder:b10,dpup:b11,dpdown:b12,dpleft:b13,dpright:b14," +
+	"leftx:a0,lefty:a1,rightx:a2,righty:a3,lefttrigger:a4,righttrigger:a5"
+
+// AttachVirtualController attaches a new virtual controller.
+// A ControllerDeviceEvent is delivered for it, as for a real controller.
+func AttachVirtualController() (*VirtualController, error) {
+	v := &VirtualController{}
+	err := doErr(func() error {
+		idx := C.attachVirtualController()
+		if idx < 0 {
+			return sdlError("SDL_JoystickAttachVirtual")
+		}
+		v.index = int(idx)
+
+		var guid [33]C.char
+		C.SDL_JoystickGetGUIDString(C.SDL_JoystickGetDeviceGUID(idx), &guid[0], C.int(len(guid)))
+		mapping := C.CString(C.GoString(&guid[0]) + "," + virtualControllerMapping)
+		defer C.free(unsafe.Pointer(mapping))
+		if C.SDL_GameControllerAddMapping(mapping) < 0 {
+			err := sdlError("SDL_GameControllerAddMapping")
+			C.detachVirtual(idx)
+			return err
+		}
+
+		// Values can only be set on an open joystick.
+		if v.joy = C.SDL_JoystickOpen(idx); v.joy == nil {
+			err := sdlError("SDL_JoystickOpen")
+			C.detachVirtual(idx)
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// Index returns the virtual controller's device index, for use with OpenController.
+func (v *VirtualController) Index() int {
+	return v.index
+}
+
+// SetButton sets whether a button of the virtual controller is down.
+// As with a real controller, the change is reported by events.
+func (v *VirtualController) SetButton(b ControllerButton, down bool) error {
+	var state C.Uint8 = C.SDL_RELEASED
+	if down {
+		state = C.SDL_PRESSED
+	}
+	return doErr(func() error {
+		if v.joy == nil {
+			return ErrControllerClosed
+		}
+		if C.setVirtualButton(v.joy, C.int(b), state) < 0 {
+			return sdlError("SDL_JoystickSetVirtualButton")
+		}
+		return nil
+	})
+}
+
+// SetAxis sets the value of an axis of the virtual controller.
+// As with a real controller, the change is reported by events.
+func (v *VirtualController) SetAxis(a ControllerAxis, value int) error {
+	return doErr(func() error {
+		if v.joy == nil {
+			return ErrControllerClosed
+		}
+		if C.setVirtualAxis(v.joy, C.int(a), C.Sint16(value)) < 0 {
+			return sdlError("SDL_JoystickSetVirtualAxis")
+		}
+		return nil
+	})
+}
+
+// Detach detaches the virtual controller, as if it were disconnected.
+// Detaching a detached controller has no effect.
+func (v *VirtualController) Detach() error {
+	err := doErr(func() error {
+		if v.joy == nil {
+			return nil
+		}
+		id := C.SDL_JoystickInstanceID(v.joy)
+		C.SDL_JoystickClose(v.joy)
+		v.joy = nil
+		// The device index may have changed as other devices came and went.
+		n := C.SDL_NumJoysticks()
+		for i := C.int(0); i < n; i++ {
+			if C.SDL_JoystickGetDeviceInstanceID(i) == id {
+				if C.detachVirtual(i) < 0 {
+					return sdlError("SDL_JoystickDetachVirtual")
+				}
+				break
+			}
+		}
+		return nil
+	})
+	if err == ErrNotRunning {
+		return nil
+	}
+	return err
+}
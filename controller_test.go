@@ -0,0 +1,101 @@
+// © 2013 the Ui Authors under the MIT license. See AUTHORS for the list of authors.
+
+package ui_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/velour/ui"
+)
+
+// NextApp returns the next application event for which match returns true,
+// skipping others, or nil if none arrives within a second.
+func nextApp(match func(ui.Event) bool) ui.Event {
+	t := time.NewTimer(time.Second)
+	defer t.Stop()
+	for {
+		select {
+		case ev := <-ui.Events():
+			if match(ev) {
+				return ev
+			}
+		case <-t.C:
+			return nil
+		}
+	}
+}
+
+func TestVirtualController(t *testing.T) {
+	v, err := ui.AttachVirtualController()
+	if err != nil && strings.Contains(err.Error(), "SDL 2.0.14") {
+		t.Skip(err)
+	}
+	if err != nil {
+		t.Fatalf("AttachVirtualController: %v", err)
+	}
+	defer v.Detach()
+
+	ev := nextApp(func(ev ui.Event) bool {
+		d, ok := ev.(*ui.ControllerDeviceEvent)
+		return ok && d.Event == ui.ControllerAdded && d.Index == v.Index()
+	})
+	if ev == nil {
+		t.Fatalf("no ControllerAdded event for index %d", v.Index())
+	}
+
+	c, err := ui.OpenController(v.Index())
+	if err != nil {
+		t.Fatalf("OpenController: %v", err)
+	}
+	defer c.Close()
+	if id := ev.(*ui.ControllerDeviceEvent).ID; id != c.ID() {
+		t.Errorf("ControllerAdded ID = %d, want the controller's ID %d", id, c.ID())
+	}
+
+	if err := v.SetButton(ui.ControllerButtonA, true); err != nil {
+		t.Fatalf("SetButton: %v", err)
+	}
+	ev = nextApp(func(ev ui.Event) bool {
+		_, ok := ev.(*ui.ControllerButtonEvent)
+		return ok
+	})
+	if b, ok := ev.(*ui.ControllerButtonEvent); !ok || b.ID != c.ID() || b.Button != ui.ControllerButtonA || !b.Down {
+		t.Errorf("got %v, want ControllerButtonEvent{ID: %d, Button: A, Down: true}", ev, c.ID())
+	}
+	if !c.Button(ui.ControllerButtonA) {
+		t.Errorf("Button(A) = false, want true")
+	}
+	if c.Button(ui.ControllerButtonB) {
+		t.Errorf("Button(B) = true, want false")
+	}
+
+	if err := v.SetAxis(ui.ControllerAxisLeftX, 12345); err != nil {
+		t.Fatalf("SetAxis: %v", err)
+	}
+	ev = nextApp(func(ev ui.Event) bool {
+		_, ok := ev.(*ui.ControllerAxisEvent)
+		return ok
+	})
+	if a, ok := ev.(*ui.ControllerAxisEvent); !ok || a.ID != c.ID() || a.Axis != ui.ControllerAxisLeftX || a.Value != 12345 {
+		t.Errorf("got %v, want ControllerAxisEvent{ID: %d, Axis: LeftX, Value: 12345}", ev, c.ID())
+	}
+	if x := c.Axis(ui.ControllerAxisLeftX); x != 12345 {
+		t.Errorf("Axis(LeftX) = %d, want 12345", x)
+	}
+
+	if err := v.Detach(); err != nil {
+		t.Fatalf("Detach: %v", err)
+	}
+	ev = nextApp(func(ev ui.Event) bool {
+		d, ok := ev.(*ui.ControllerDeviceEvent)
+		return ok && d.Event == ui.ControllerRemoved
+	})
+	if d, ok := ev.(*ui.ControllerDeviceEvent); !ok || d.ID != c.ID() {
+		t.Errorf("got %v, want ControllerRemoved for ID %d", ev, c.ID())
+	}
+	if err := v.SetButton(ui.ControllerButtonA, false); err != ui.ErrControllerClosed {
+		t.Errorf("SetButton after Detach = %v, want ErrControllerClosed", err)
+	}
+}
@@ -0,0 +1,218 @@
+// © 2013 the Ui Authors under the MIT license. See AUTHORS for the list of authors.
+
+package ui
+
+import (
+	"context"
+	"sync"
+)
+
+// A Dispatcher calls registered handlers for the events of an event channel,
+// such as the one returned by Window.Events, and fans the events out to subscribers.
+//
+// Handlers are called in the order in which they were registered.  A handler
+// returns true to stop propagation: the event's remaining handlers are not called.
+// Subscribers receive every event, regardless of propagation.
+//
+// Handlers are called from the go routine that calls Dispatch or Run,
+// and they may register other handlers.
+// It is safe to use a Dispatcher from multiple go routines.
+type Dispatcher struct {
+	mu       sync.Mutex
+	handlers []func(Event) bool
+	subs     map[*Subscription]bool
+	closed   bool
+}
+
+// NewDispatcher returns a new Dispatcher with no handlers or subscribers.
+func NewDispatcher() *Dispatcher {
+	return &Dispatcher{subs: make(map[*Subscription]bool)}
+}
+
+// OnEvent registers a handler called for every event.
+func (d *Dispatcher) OnEvent(f func(Event) bool) {
+	d.mu.Lock()
+	d.handlers = append(d.handlers, f)
+	d.mu.Unlock()
+}
+
+// OnKey registers a handler called for each KeyboardEvent.
+func (d *Dispatcher) OnKey(f func(*KeyboardEvent) bool) {
+	d.OnEvent(func(ev Event) bool {
+		if e, ok := ev.(*KeyboardEvent); ok {
+			return f(e)
+		}
+		return false
+	})
+}
+
+// OnMouseButton registers a handler called for each MouseButtonEvent.
+func (d *Dispatcher) OnMouseButton(f func(*MouseButtonEvent) bool) {
+	d.OnEvent(func(ev Event) bool {
+		if e, ok := ev.(*MouseButtonEvent); ok {
+			return f(e)
+		}
+		return false
+	})
+}
+
+// OnMouseMotion registers a handler called for each MouseMotionEvent.
+func (d *Dispatcher) OnMouseMotion(f func(*MouseMotionEvent) bool) {
+	d.OnEvent(func(ev Event) bool {
+		if e, ok := ev.(*MouseMotionEvent); ok {
+			return f(e)
+		}
+		return false
+	})
+}
+
+// OnWheel registers a handler called for each MouseWheelEvent.
+func (d *Dispatcher) OnWheel(f func(*MouseWheelEvent) bool) {
+	d.OnEvent(func(ev Event) bool {
+		if e, ok := ev.(*MouseWheelEvent); ok {
+			return f(e)
+		}
+		return false
+	})
+}
+
+// OnWindow registers a handler called for each WindowEvent of the given kind.
+func (d *Dispatcher) OnWindow(kind WindowEventKind, f func(*WindowEvent) bool) {
+	d.OnEvent(func(ev Event) bool {
+		if e, ok := ev.(*WindowEvent); ok && e.Event == kind {
+			return f(e)
+		}
+		return false
+	})
+}
+
+// OnClose registers a handler called when the window manager requests
+// that a window be closed.  The window is not closed unless the handler
+// destroys it.
+func (d *Dispatcher) OnClose(f func(*Window) bool) {
+	d.OnWindow(WindowClose, func(e *WindowEvent) bool {
+		return f(e.Window())
+	})
+}
+
+// Dispatch sends the event to the subscribers, and then calls its handlers
+// until one stops propagation.  It returns whether propagation was stopped.
+func (d *Dispatcher) Dispatch(ev Event) bool {
+	d.mu.Lock()
+	subs := make([]*Subscription, 0, len(d.subs))
+	for s := range d.subs {
+		subs = append(subs, s)
+	}
+	handlers := d.handlers
+	d.mu.Unlock()
+
+	// Sending may block, so it is done without the Dispatcher locked.
+	for _, s := range subs {
+		s.send(ev)
+	}
+	for _, h := range handlers {
+		if h(ev) {
+			return true
+		}
+	}
+	return false
+}
+
+// Run dispatches each event received from events until the channel is closed,
+// and then closes the channels of all subscriptions.
+// If the context is done first, Run returns its error, and subscriptions remain open.
+func (d *Dispatcher) Run(ctx context.Context, events <-chan Event) error {
+	for {
+		select {
+		case ev, ok := <-events:
+			if !ok {
+				d.Close()
+				return nil
+			}
+			d.Dispatch(ev)
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// Close closes the channels of all subscriptions.
+// Subscriptions made after Close have closed channels.
+func (d *Dispatcher) Close() {
+	d.mu.Lock()
+	subs := d.subs
+	d.subs = make(map[*Subscription]bool)
+	d.closed = true
+	d.mu.Unlock()
+
+	for s := range subs {
+		s.close()
+	}
+}
+
+// A Subscription receives all events dispatched by a Dispatcher on its own channel.
+type Subscription struct {
+	d *Dispatcher
+	q *eventQueue
+
+	// Mu serializes sending on the channel with closing it.
+	mu     sync.Mutex
+	closed bool
+}
+
+// Subscribe returns a new subscription to the dispatched events.
+// The options configure the buffering of its channel; CoalesceMotion is ignored.
+// A subscriber that does not keep up loses events as the Overflow policy says,
+// without affecting other subscribers, except that the Block policy delays them.
+func (d *Dispatcher) Subscribe(opts EventOptions) *Subscription {
+	s := &Subscription{d: d, q: newEventQueue(opts)}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.closed {
+		s.closed = true
+		close(s.q.ch)
+	} else {
+		d.subs[s] = true
+	}
+	return s
+}
+
+// Events returns the subscription's event channel.
+// It is closed when the subscription or its Dispatcher is closed.
+func (s *Subscription) Events() <-chan Event {
+	return s.q.ch
+}
+
+// DroppedEvents returns the number of events discarded because the channel was full.
+func (s *Subscription) DroppedEvents() uint64 {
+	return s.q.droppedEvents()
+}
+
+// Close ends the subscription and closes its channel.
+// If an event is being sent with the Block policy, Close waits for the send to finish.
+// Closing a subscription that is already closed has no effect.
+func (s *Subscription) Close() {
+	s.d.mu.Lock()
+	delete(s.d.subs, s)
+	s.d.mu.Unlock()
+	s.close()
+}
+
+// Send sends an event on the channel, unless the subscription is closed.
+func (s *Subscription) send(ev Event) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.closed {
+		s.q.send(ev)
+	}
+}
+
+// Close closes the channel, unless it is already closed.
+func (s *Subscription) close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.closed {
+		s.closed = true
+		close(s.q.ch)
+	}
+}
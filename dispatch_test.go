@@ -0,0 +1,147 @@
+// © 2013 the Ui Authors under the MIT license. See AUTHORS for the list of authors.
+
+package ui
+
+import (
+	"context"
+	"sync"
+	"testing"
+)
+
+func TestDispatchPropagation(t *testing.T) {
+	d := NewDispatcher()
+	var calls []string
+	d.OnEvent(func(Event) bool {
+		calls = append(calls, "first")
+		return false
+	})
+	d.OnKey(func(*KeyboardEvent) bool {
+		calls = append(calls, "key")
+		return true
+	})
+	d.OnEvent(func(Event) bool {
+		calls = append(calls, "last")
+		return false
+	})
+	s := d.Subscribe(EventOptions{})
+
+	key := &KeyboardEvent{Down: true}
+	if !d.Dispatch(key) {
+		t.Errorf("Dispatch(key) = false, want true")
+	}
+	if len(calls) != 2 || calls[0] != "first" || calls[1] != "key" {
+		t.Errorf("Dispatch(key) called %v, want [first key]", calls)
+	}
+
+	calls = nil
+	motion := &MouseMotionEvent{}
+	if d.Dispatch(motion) {
+		t.Errorf("Dispatch(motion) = true, want false")
+	}
+	if len(calls) != 2 || calls[0] != "first" || calls[1] != "last" {
+		t.Errorf("Dispatch(motion) called %v, want [first last]", calls)
+	}
+
+	// Subscribers receive events whose propagation was stopped.
+	checkEvents(t, "subscriber", drain(s.q), key, motion)
+}
+
+func TestDispatchRegisterInHandler(t *testing.T) {
+	d := NewDispatcher()
+	added := false
+	d.OnEvent(func(Event) bool {
+		d.OnEvent(func(Event) bool {
+			added = true
+			return false
+		})
+		return false
+	})
+	d.Dispatch(&QuitEvent{})
+	if added {
+		t.Errorf("handler registered during Dispatch was called for the same event")
+	}
+	d.Dispatch(&QuitEvent{})
+	if !added {
+		t.Errorf("handler registered during Dispatch was not called for the next event")
+	}
+}
+
+func TestDispatchFanOut(t *testing.T) {
+	d := NewDispatcher()
+	a := d.Subscribe(EventOptions{})
+	b := d.Subscribe(EventOptions{})
+	slow := d.Subscribe(EventOptions{BufferSize: 1, Overflow: DropNewest})
+
+	e1, e2, e3 := &QuitEvent{}, &QuitEvent{}, &QuitEvent{}
+	d.Dispatch(e1)
+	d.Dispatch(e2)
+	b.Close()
+	d.Dispatch(e3)
+
+	checkEvents(t, "a", drain(a.q), e1, e2, e3)
+	checkEvents(t, "slow", drain(slow.q), e1)
+	if n := slow.DroppedEvents(); n != 2 {
+		t.Errorf("slow subscriber dropped %d events, want 2", n)
+	}
+	if n := a.DroppedEvents(); n != 0 {
+		t.Errorf("subscriber a dropped %d events, want 0", n)
+	}
+	checkEvents(t, "closed b", drain(b.q), e1, e2)
+	if _, ok := <-b.Events(); ok {
+		t.Errorf("closed subscription's channel is open")
+	}
+	b.Close()
+}
+
+func TestDispatcherClose(t *testing.T) {
+	d := NewDispatcher()
+	s := d.Subscribe(EventOptions{})
+	events := make(chan Event, 1)
+	events <- &QuitEvent{}
+	close(events)
+	if err := d.Run(context.Background(), events); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if len(drain(s.q)) != 1 {
+		t.Errorf("subscriber did not receive the event")
+	}
+	if _, ok := <-s.Events(); ok {
+		t.Errorf("subscription's channel is open after Run returned")
+	}
+	s.Close()
+
+	late := d.Subscribe(EventOptions{})
+	if _, ok := <-late.Events(); ok {
+		t.Errorf("subscription made after Close has an open channel")
+	}
+	d.Dispatch(&QuitEvent{})
+}
+
+func TestDispatchCloseConcurrently(t *testing.T) {
+	d := NewDispatcher()
+	subs := make([]*Subscription, 10)
+	for i := range subs {
+		subs[i] = d.Subscribe(EventOptions{BufferSize: 1, Overflow: DropOldest})
+	}
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				d.Dispatch(&QuitEvent{})
+			}
+		}()
+	}
+	for _, s := range subs[:5] {
+		s.Close()
+	}
+	d.Close()
+	wg.Wait()
+	for _, s := range subs {
+		drain(s.q)
+		if _, ok := <-s.Events(); ok {
+			t.Errorf("subscription's channel is open after Close")
+		}
+	}
+}
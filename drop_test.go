@@ -0,0 +1,124 @@
+// © 2013 the Ui Authors under the MIT license. See AUTHORS for the list of authors.
+
+package ui
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+// NextDrop returns the next DropEvent delivered to the window, or nil if the next
+// event other than a WindowEvent is not a DropEvent or none arrives within timeout.
+func nextDrop(win *Window, timeout time.Duration) *DropEvent {
+	t := time.NewTimer(timeout)
+	defer t.Stop()
+	for {
+		select {
+		case ev := <-win.Events():
+			if _, ok := ev.(*WindowEvent); ok {
+				continue
+			}
+			d, _ := ev.(*DropEvent)
+			return d
+		case <-t.C:
+			return nil
+		}
+	}
+}
+
+func str(s string) *string { return &s }
+
+func newDropWindow(t *testing.T) *Window {
+	t.Helper()
+	win, err := NewWindowErr("test", 8, 8)
+	if err != nil {
+		t.Fatalf("NewWindowErr: %v", err)
+	}
+	return win
+}
+
+func checkDrop(t *testing.T, name string, got *DropEvent, files, texts []string) {
+	t.Helper()
+	if got == nil {
+		t.Errorf("%s: no DropEvent delivered", name)
+		return
+	}
+	if !reflect.DeepEqual(got.Files, files) || !reflect.DeepEqual(got.Texts, texts) {
+		t.Errorf("%s: got %v, want Files: %q, Texts: %q", name, got, files, texts)
+	}
+}
+
+func TestInjectDrop(t *testing.T) {
+	win := newDropWindow(t)
+	defer win.Destroy()
+
+	files, texts := []string{"/tmp/a", "/tmp/b c"}, []string{"hello"}
+	if err := win.Inject(&DropEvent{Files: files, Texts: texts}); err != nil {
+		t.Fatalf("Inject: %v", err)
+	}
+	d := nextDrop(win, time.Second)
+	checkDrop(t, "Inject", d, files, texts)
+	if d != nil && d.Window() != win {
+		t.Errorf("Inject: Window() = %v, want the injecting window", d.Window())
+	}
+
+	// An empty drop is not delivered.
+	if err := win.Inject(&DropEvent{}); err != nil {
+		t.Fatalf("Inject of an empty drop: %v", err)
+	}
+	if err := win.Inject(&DropEvent{Texts: []string{"next"}}); err != nil {
+		t.Fatalf("Inject: %v", err)
+	}
+	checkDrop(t, "after an empty drop", nextDrop(win, time.Second), nil, []string{"next"})
+}
+
+func TestDropWithoutBegin(t *testing.T) {
+	win := newDropWindow(t)
+	defer win.Destroy()
+
+	// Without a DROPBEGIN, as on platforms that do not send one,
+	// each item is delivered as its own drop.
+	err := win.do(func() error {
+		if err := win.pushDrop(dropFile, str("/tmp/a")); err != nil {
+			return err
+		}
+		if err := win.pushDrop(dropText, str("hello")); err != nil {
+			return err
+		}
+		return win.pushDrop(dropComplete, nil)
+	})
+	if err != nil {
+		t.Fatalf("pushDrop: %v", err)
+	}
+	checkDrop(t, "file", nextDrop(win, time.Second), []string{"/tmp/a"}, nil)
+	checkDrop(t, "text", nextDrop(win, time.Second), nil, []string{"hello"})
+	if d := nextDrop(win, 50*time.Millisecond); d != nil {
+		t.Errorf("DROPCOMPLETE without DROPBEGIN delivered %v", d)
+	}
+}
+
+func TestDropDestroy(t *testing.T) {
+	win := newDropWindow(t)
+	err := win.do(func() error {
+		if err := win.pushDrop(dropBegin, nil); err != nil {
+			return err
+		}
+		return win.pushDrop(dropFile, str("/tmp/a"))
+	})
+	if err != nil {
+		t.Fatalf("pushDrop: %v", err)
+	}
+
+	// The pushed events are decoded before the main loop runs the next queued function.
+	var pending bool
+	do(func() { _, pending = pendingDrops[win.id] })
+	if !pending {
+		t.Fatalf("no pending drop after DROPBEGIN")
+	}
+	win.Destroy()
+	do(func() { _, pending = pendingDrops[win.id] })
+	if pending {
+		t.Errorf("pending drop not freed by Destroy")
+	}
+}
@@ -0,0 +1,74 @@
+// © 2013 the Ui Authors under the MIT license. See AUTHORS for the list of authors.
+
+package ui
+
+/*
+#include "ui.h"
+*/
+import "C"
+
+import (
+	"errors"
+	"strconv"
+)
+
+// ErrNotRunning is returned when an operation requires the user interface,
+// but it has not been started or it has been shut down.
+var ErrNotRunning = errors.New("ui: not running")
+
+// ErrDestroyed is returned when an operation is attempted on a destroyed window.
+var ErrDestroyed = errors.New("ui: window destroyed")
+
+// ErrControllerClosed is returned when an operation is attempted on a closed controller.
+var ErrControllerClosed = errors.New("ui: controller closed")
+
+// An Error is an error reported by SDL.
+type Error struct {
+	// Func is the name of the SDL function that failed.
+	Func string
+
+	// Msg is the error string reported by SDL_GetError.
+	Msg string
+}
+
+func (e *Error) Error() string {
+	return e.Func + ": " + e.Msg
+}
+
+// SdlError returns an *Error for the failed SDL function fn,
+// using the message from SDL_GetError.
+func sdlError(fn string) error {
+	return &Error{Func: fn, Msg: C.GoString(C.SDL_GetError())}
+}
+
+// A LoadError records a failure to load a resource file,
+// such as an image, a font, or a sound.
+type LoadError struct {
+	// Path is the path of the file that failed to load.
+	Path string
+
+	// Err is the underlying error.
+	Err error
+}
+
+func (e *LoadError) Error() string {
+	return "load " + e.Path + ": " + e.Err.Error()
+}
+
+// Unwrap returns the underlying error.
+func (e *LoadError) Unwrap() error {
+	return e.Err
+}
+
+// A ParseError records a failure to parse a name, such as a key or shortcut name.
+type ParseError struct {
+	// Kind is the kind of name, such as "key".
+	Kind string
+
+	// Text is the text that failed to parse.
+	Text string
+}
+
+func (e *ParseError) Error() string {
+	return "ui: unknown " + e.Kind + " " + strconv.Quote(e.Text)
+}
@@ -8,39 +8,206 @@ package ui
 Uint32 eventType(SDL_Event *e) {
 	return e->type;
 }
+
+// The window of a touch event was added in SDL 2.0.12.
+// With older versions, touch events have no window.
+static Uint32 fingerWindowID(SDL_TouchFingerEvent *e) {
+#if SDL_VERSION_ATLEAST(2, 0, 12)
+	return e->windowID;
+#else
+	return 0;
+#endif
+}
+
+static void setFingerWindowID(SDL_TouchFingerEvent *e, Uint32 id) {
+#if SDL_VERSION_ATLEAST(2, 0, 12)
+	e->windowID = id;
+#endif
+}
+
+// Precise wheel amounts were added in SDL 2.0.18.
+// With older versions, they are the whole-step amounts.
+static void wheelPrecise(SDL_MouseWheelEvent *e, float *x, float *y) {
+#if SDL_VERSION_ATLEAST(2, 0, 18)
+	*x = e->preciseX;
+	*y = e->preciseY;
+#else
+	*x = e->x;
+	*y = e->y;
+#endif
+}
+
+static void setWheelPrecise(SDL_MouseWheelEvent *e, float x, float y) {
+#if SDL_VERSION_ATLEAST(2, 0, 18)
+	e->preciseX = x;
+	e->preciseY = y;
+#endif
+}
 */
 import "C"
 
 import (
+	"fmt"
 	"strconv"
+	"time"
 	"unsafe"
 )
 
+// An Event is an event delivered by the user interface.
+type Event interface {
+	// Window returns the window to which the event was delivered,
+	// or nil if the event is not associated with a window.
+	Window() *Window
+
+	// Timestamp returns the time at which the event occurred,
+	// relative to when the user interface was started.
+	Timestamp() time.Duration
+
+	// String returns a description of the event, for debugging.
+	String() string
+}
+
+// An eventHeader holds the data common to all events.
+type eventHeader struct {
+	winID windowID
+	win   *Window
+	time  time.Duration
+}
+
+// NewEventHeader returns an eventHeader for the given SDL window ID and timestamp.
+// It must be called from the main go routine.
+func newEventHeader(id C.Uint32, timestamp C.Uint32) eventHeader {
+	return eventHeader{
+		winID: windowID(id),
+		win:   windows[windowID(id)],
+		time:  time.Duration(timestamp) * time.Millisecond,
+	}
+}
+
+// Window returns the window to which the event was delivered,
+// or nil if the event is not associated with a window.
+func (h *eventHeader) Window() *Window {
+	return h.win
+}
+
+// Timestamp returns the time at which the event occurred,
+// relative to when the user interface was started.
+func (h *eventHeader) Timestamp() time.Duration {
+	return h.time
+}
+
+func (h *eventHeader) windowID() windowID {
+	return h.winID
+}
+
 // PollEvent polls for currently pending events.
 //
 // Events not supported by this binding set are discarded.
-func pollEvent() interface{} {
+func pollEvent() Event {
 	for {
 		var ev C.SDL_Event
 		if C.SDL_PollEvent(&ev) == 0 {
 			return nil
 		}
-
-		switch C.eventType(&ev) {
-		case C.SDL_KEYDOWN, C.SDL_KEYUP:
-			return newKeyboardEvent(&ev)
-		case C.SDL_WINDOWEVENT:
-			return newWindowEvent(&ev)
-		case C.SDL_MOUSEWHEEL:
-			return newMouseWheelEvent(&ev)
-		case C.SDL_MOUSEMOTION:
-			return newMouseMotionEvent(&ev)
-		case C.SDL_MOUSEBUTTONDOWN, C.SDL_MOUSEBUTTONUP:
-			return newMouseButtonEvent(&ev)
+		if e := decodeEvent(&ev); e != nil {
+			return e
 		}
 	}
 }
 
+// WaitEvent waits for the next event for up to timeout milliseconds,
+// or forever if timeout is negative.
+// It returns nil if no event arrived or if the event is not supported by this binding set.
+func waitEvent(timeout C.int) Event {
+	var ev C.SDL_Event
+	if C.SDL_WaitEventTimeout(&ev, timeout) == 0 {
+		return nil
+	}
+	return decodeEvent(&ev)
+}
+
+// DecodeEvent returns the event corresponding to an SDL event,
+// or nil if the event is not supported by this binding set.
+func decodeEvent(ev *C.SDL_Event) Event {
+	switch C.eventType(ev) {
+	case C.SDL_KEYDOWN, C.SDL_KEYUP:
+		return newKeyboardEvent(ev)
+	case C.SDL_WINDOWEVENT:
+		return newWindowEvent(ev)
+	case C.SDL_MOUSEWHEEL:
+		return newMouseWheelEvent(ev)
+	case C.SDL_MOUSEMOTION:
+		return newMouseMotionEvent(ev)
+	case C.SDL_MOUSEBUTTONDOWN, C.SDL_MOUSEBUTTONUP:
+		return newMouseButtonEvent(ev)
+	case C.SDL_TEXTINPUT:
+		return newTextInputEvent(ev)
+	case C.SDL_TEXTEDITING:
+		return newTextEditingEvent(ev)
+	case C.SDL_CONTROLLERDEVICEADDED, C.SDL_CONTROLLERDEVICEREMOVED, C.SDL_CONTROLLERDEVICEREMAPPED:
+		return newControllerDeviceEvent(ev)
+	case C.SDL_CONTROLLERBUTTONDOWN, C.SDL_CONTROLLERBUTTONUP:
+		return newControllerButtonEvent(ev)
+	case C.SDL_CONTROLLERAXISMOTION:
+		return newControllerAxisEvent(ev)
+	case C.SDL_FINGERDOWN, C.SDL_FINGERUP, C.SDL_FINGERMOTION:
+		return newTouchEvent(ev)
+	case C.SDL_MULTIGESTURE:
+		return newMultiGestureEvent(ev)
+	case C.SDL_DROPBEGIN, C.SDL_DROPFILE, C.SDL_DROPTEXT, C.SDL_DROPCOMPLETE:
+		return decodeDropEvent(ev)
+	case C.SDL_QUIT:
+		return newQuitEvent(ev)
+	case C.SDL_CLIPBOARDUPDATE:
+		return newClipboardUpdateEvent(ev)
+	case userEvent:
+		return newUserEvent(ev)
+	}
+	return nil
+}
+
+// An appEvent is an event for the application as a whole, not for a single window.
+// App events are delivered on the channel returned by Events.
+type appEvent interface {
+	Event
+	appEvent()
+}
+
+// A QuitEvent is a request to quit the application.  It is sent when the
+// last window is closed, when the operating system asks the application to
+// quit, or, if Options.Signals is set, when the program is interrupted.
+type QuitEvent struct {
+	eventHeader
+}
+
+func newQuitEvent(ev *C.SDL_Event) *QuitEvent {
+	e := (*C.SDL_QuitEvent)(unsafe.Pointer(ev))
+	return &QuitEvent{eventHeader: newEventHeader(0, e.timestamp)}
+}
+
+func (e *QuitEvent) appEvent() {}
+
+func (e *QuitEvent) String() string {
+	return "QuitEvent{}"
+}
+
+// A ClipboardUpdateEvent says that the contents of the clipboard have changed.
+type ClipboardUpdateEvent struct {
+	eventHeader
+}
+
+func newClipboardUpdateEvent(ev *C.SDL_Event) *ClipboardUpdateEvent {
+	e := (*C.SDL_CommonEvent)(unsafe.Pointer(ev))
+	return &ClipboardUpdateEvent{eventHeader: newEventHeader(0, e.timestamp)}
+}
+
+func (e *ClipboardUpdateEvent) appEvent() {}
+
+func (e *ClipboardUpdateEvent) String() string {
+	return "ClipboardUpdateEvent{}"
+}
+
+// A WindowEventKind is the kind of a WindowEvent.
 type WindowEventKind C.Uint32
 
 const (
@@ -114,68 +281,380 @@ func (w WindowEventKind) String() string {
 
 // A WindowEvent is a structure that contains window state change event data.
 type WindowEvent struct {
-	winID        windowID
+	eventHeader
 	Event        WindowEventKind
 	Data1, Data2 int
 }
 
-func (e WindowEvent) windowID() windowID {
-	return e.winID
-}
-
 func newWindowEvent(ev *C.SDL_Event) *WindowEvent {
 	e := (*C.SDL_WindowEvent)(unsafe.Pointer(ev))
 	return &WindowEvent{
-		winID: windowID(e.windowID),
-		Event: WindowEventKind(e.event),
-		Data1: int(e.data1),
-		Data2: int(e.data2),
+		eventHeader: newEventHeader(e.windowID, e.timestamp),
+		Event:       WindowEventKind(e.event),
+		Data1:       int(e.data1),
+		Data2:       int(e.data2),
 	}
 }
 
+func (e *WindowEvent) String() string {
+	return fmt.Sprintf("WindowEvent{Event: %v, Data1: %d, Data2: %d}", e.Event, e.Data1, e.Data2)
+}
+
+// A KeyboardEvent is a key press or release.
 type KeyboardEvent struct {
-	winID  windowID
-	Down   bool
+	eventHeader
+
+	// Down is true if the key was pressed, and false if it was released.
+	Down bool
+
+	// Repeat is true if the event is a key repeat.
 	Repeat bool
-	Key    Key
-}
 
-func (e KeyboardEvent) windowID() windowID {
-	return e.winID
+	// Key is the key, as translated by the current keyboard layout.
+	Key Key
+
+	// Scancode is the physical key, independent of the keyboard layout.
+	Scancode Scancode
+
+	// Mod is the modifier keys that were down when the event occurred.
+	Mod KeyMod
 }
 
 func newKeyboardEvent(ev *C.SDL_Event) *KeyboardEvent {
 	e := (*C.SDL_KeyboardEvent)(unsafe.Pointer(ev))
 	return &KeyboardEvent{
-		winID:  windowID(e.windowID),
-		Down:   e.state == C.SDL_PRESSED,
-		Repeat: e.repeat != 0,
-		Key:    Key(e.keysym.sym),
+		eventHeader: newEventHeader(e.windowID, e.timestamp),
+		Down:        e.state == C.SDL_PRESSED,
+		Repeat:      e.repeat != 0,
+		Key:         Key(e.keysym.sym),
+		Scancode:    Scancode(e.keysym.scancode),
+		Mod:         KeyMod(e.keysym.mod),
+	}
+}
+
+func (e *KeyboardEvent) String() string {
+	return fmt.Sprintf("KeyboardEvent{Key: %v, Scancode: %v, Mod: %v, Down: %t, Repeat: %t}",
+		e.Key, e.Scancode, e.Mod, e.Down, e.Repeat)
+}
+
+// A TextInputEvent is text typed by the user, after any composition by an input method.
+// Text input events are delivered while text input is started; see Window.StartTextInput.
+type TextInputEvent struct {
+	eventHeader
+
+	// Text is the UTF-8 encoded text.
+	Text string
+}
+
+func newTextInputEvent(ev *C.SDL_Event) *TextInputEvent {
+	e := (*C.SDL_TextInputEvent)(unsafe.Pointer(ev))
+	return &TextInputEvent{
+		eventHeader: newEventHeader(e.windowID, e.timestamp),
+		Text:        C.GoString(&e.text[0]),
 	}
 }
 
+func (e *TextInputEvent) String() string {
+	return fmt.Sprintf("TextInputEvent{Text: %q}", e.Text)
+}
+
+// A TextEditingEvent is a change to the text being composed by an input method (IME).
+// The composition should be displayed at the caret until it is committed by a
+// TextInputEvent or cancelled by a TextEditingEvent with empty Text.
+type TextEditingEvent struct {
+	eventHeader
+
+	// Text is the UTF-8 encoded composition.
+	Text string
+
+	// Start is the position of the cursor within the composition, in runes.
+	Start int
+
+	// Length is the number of runes selected after Start.
+	Length int
+}
+
+func newTextEditingEvent(ev *C.SDL_Event) *TextEditingEvent {
+	e := (*C.SDL_TextEditingEvent)(unsafe.Pointer(ev))
+	return &TextEditingEvent{
+		eventHeader: newEventHeader(e.windowID, e.timestamp),
+		Text:        C.GoString(&e.text[0]),
+		Start:       int(e.start),
+		Length:      int(e.length),
+	}
+}
+
+func (e *TextEditingEvent) String() string {
+	return fmt.Sprintf("TextEditingEvent{Text: %q, Start: %d, Length: %d}", e.Text, e.Start, e.Length)
+}
+
+// A MouseMotionEvent is a movement of the mouse.
 type MouseMotionEvent struct {
-	winID            windowID
+	eventHeader
+
+	// X and Y are the mouse position relative to the window,
+	// and Xrel and Yrel are the change in position since the last MouseMotionEvent.
 	X, Y, Xrel, Yrel int
 }
 
 func newMouseMotionEvent(ev *C.SDL_Event) *MouseMotionEvent {
 	e := (*C.SDL_MouseMotionEvent)(unsafe.Pointer(ev))
 	return &MouseMotionEvent{
-		winID: windowID(e.windowID),
-		X:     int(e.x),
-		Y:     int(e.y),
-		Xrel:  int(e.xrel),
-		Yrel:  int(e.yrel),
+		eventHeader: newEventHeader(e.windowID, e.timestamp),
+		X:           int(e.x),
+		Y:           int(e.y),
+		Xrel:        int(e.xrel),
+		Yrel:        int(e.yrel),
 	}
 }
 
-func (e MouseMotionEvent) windowID() windowID {
-	return e.winID
+func (e *MouseMotionEvent) String() string {
+	return fmt.Sprintf("MouseMotionEvent{X: %d, Y: %d, Xrel: %d, Yrel: %d}", e.X, e.Y, e.Xrel, e.Yrel)
 }
 
+// A TouchEventKind is the kind of a TouchEvent.
+type TouchEventKind C.Uint32
+
+const (
+	// TouchDown says that a finger touched the device.
+	TouchDown TouchEventKind = C.SDL_FINGERDOWN
+
+	// TouchUp says that a finger was lifted from the device.
+	TouchUp TouchEventKind = C.SDL_FINGERUP
+
+	// TouchMotion says that a finger moved on the device.
+	TouchMotion TouchEventKind = C.SDL_FINGERMOTION
+)
+
+var touchEventKindNames = map[TouchEventKind]string{
+	TouchDown:   "TouchDown",
+	TouchUp:     "TouchUp",
+	TouchMotion: "TouchMotion",
+}
+
+func (k TouchEventKind) String() string {
+	if n, ok := touchEventKindNames[k]; ok {
+		return n
+	}
+	return "Unknown(" + strconv.Itoa(int(k)) + ")"
+}
+
+// A TouchEvent is a finger touching, moving on, or leaving a touch device.
+type TouchEvent struct {
+	eventHeader
+	Event TouchEventKind
+
+	// TouchID identifies the touch device, and FingerID identifies
+	// the finger for as long as it touches the device.
+	TouchID, FingerID int64
+
+	// X and Y are the position of the finger, normalized to the range 0 to 1,
+	// and DX and DY are the distance moved, normalized to the range -1 to 1.
+	X, Y, DX, DY float64
+
+	// WinX and WinY are the position of the finger in window coordinates.
+	WinX, WinY int
+
+	// Pressure is the pressure of the finger, from 0 to 1.
+	Pressure float64
+}
+
+// TouchWindows maps touch devices to the window of their most recent touch.
+// It is only accessed from the main go routine.
+var touchWindows = make(map[C.SDL_TouchID]*Window)
+
+func newTouchEvent(ev *C.SDL_Event) *TouchEvent {
+	e := (*C.SDL_TouchFingerEvent)(unsafe.Pointer(ev))
+	id := C.fingerWindowID(e)
+	t := &TouchEvent{
+		eventHeader: newEventHeader(id, e.timestamp),
+		Event:       TouchEventKind(e._type),
+		TouchID:     int64(e.touchId),
+		FingerID:    int64(e.fingerId),
+		X:           float64(e.x),
+		Y:           float64(e.y),
+		DX:          float64(e.dx),
+		DY:          float64(e.dy),
+		Pressure:    float64(e.pressure),
+	}
+	if t.win == nil && id == 0 {
+		// Some touch devices are not associated with a window,
+		// and SDL before 2.0.12 does not report the window.
+		if win, ok := touchWindows[e.touchId]; ok {
+			t.win = win
+		} else {
+			t.win = focusWindow()
+		}
+	}
+	if t.win != nil {
+		t.winID = t.win.id
+		touchWindows[e.touchId] = t.win
+		t.WinX, t.WinY = t.win.normalizedPoint(t.X, t.Y)
+	}
+	return t
+}
+
+func (e *TouchEvent) String() string {
+	return fmt.Sprintf("TouchEvent{Event: %v, TouchID: %d, FingerID: %d, X: %g, Y: %g, DX: %g, DY: %g, WinX: %d, WinY: %d, Pressure: %g}",
+		e.Event, e.TouchID, e.FingerID, e.X, e.Y, e.DX, e.DY, e.WinX, e.WinY, e.Pressure)
+}
+
+// A MultiGestureEvent is a gesture made with multiple fingers on a touch device,
+// such as a pinch or a rotation.  It is delivered to the window of the device's
+// most recent TouchEvent.
+type MultiGestureEvent struct {
+	eventHeader
+
+	// TouchID identifies the touch device.
+	TouchID int64
+
+	// DTheta is the amount that the fingers rotated, in radians,
+	// and DDist is the amount that they pinched, normalized.
+	DTheta, DDist float64
+
+	// X and Y are the center of the gesture, normalized to the range 0 to 1.
+	X, Y float64
+
+	// WinX and WinY are the center of the gesture in window coordinates.
+	WinX, WinY int
+
+	// NumFingers is the number of fingers used in the gesture.
+	NumFingers int
+}
+
+func newMultiGestureEvent(ev *C.SDL_Event) *MultiGestureEvent {
+	e := (*C.SDL_MultiGestureEvent)(unsafe.Pointer(ev))
+	g := &MultiGestureEvent{
+		eventHeader: newEventHeader(0, e.timestamp),
+		TouchID:     int64(e.touchId),
+		DTheta:      float64(e.dTheta),
+		DDist:       float64(e.dDist),
+		X:           float64(e.x),
+		Y:           float64(e.y),
+		NumFingers:  int(e.numFingers),
+	}
+	// SDL does not say which window a gesture is for.
+	if win, ok := touchWindows[e.touchId]; ok {
+		g.win = win
+	} else {
+		g.win = focusWindow()
+	}
+	if g.win != nil {
+		g.winID = g.win.id
+		g.WinX, g.WinY = g.win.normalizedPoint(g.X, g.Y)
+	}
+	return g
+}
+
+func (e *MultiGestureEvent) String() string {
+	return fmt.Sprintf("MultiGestureEvent{TouchID: %d, DTheta: %g, DDist: %g, X: %g, Y: %g, WinX: %d, WinY: %d, NumFingers: %d}",
+		e.TouchID, e.DTheta, e.DDist, e.X, e.Y, e.WinX, e.WinY, e.NumFingers)
+}
+
+// SetTouchWindow addresses a touch event to a window.
+// It must be called from the main go routine.
+func setTouchWindow(e *C.SDL_TouchFingerEvent, win *Window) {
+	C.setFingerWindowID(e, C.Uint32(win.id))
+	// Without a window ID, the event is delivered to the device's most recent window.
+	touchWindows[e.touchId] = win
+}
+
+// SetWheelPrecise sets the precise amounts of a mouse wheel event,
+// if the SDL version has them.
+func setWheelPrecise(e *C.SDL_MouseWheelEvent, x, y float64) {
+	C.setWheelPrecise(e, C.float(x), C.float(y))
+}
+
+// FocusWindow returns the window with mouse focus, or the only window if
+// there is just one, or nil.  It must be called from the main go routine.
+func focusWindow() *Window {
+	if w := C.SDL_GetMouseFocus(); w != nil {
+		if win, ok := windows[windowID(C.SDL_GetWindowID(w))]; ok {
+			return win
+		}
+	}
+	if len(windows) == 1 {
+		for _, win := range windows {
+			return win
+		}
+	}
+	return nil
+}
+
+// NormalizedPoint returns the window coordinates of a point normalized to the range 0 to 1.
+// It must be called from the main go routine.
+func (win *Window) normalizedPoint(x, y float64) (int, int) {
+	var w, h C.int
+	C.SDL_GetWindowSize(win.win, &w, &h)
+	return int(x * float64(w)), int(y * float64(h))
+}
+
+// A DropEvent is files or text dropped onto a window.
+// All of the items of a single drag-and-drop operation are delivered together in one DropEvent.
+// Drops that are not onto a window, such as onto a dock icon, are not delivered.
+type DropEvent struct {
+	eventHeader
+
+	// Files are the paths of the dropped files.
+	Files []string
+
+	// Texts are the dropped texts.
+	Texts []string
+}
+
+// PendingDrops maps windows to the drops that have begun but not completed.
+// It is only accessed from the main go routine.
+var pendingDrops = make(map[windowID]*DropEvent)
+
+// DecodeDropEvent returns the DropEvent for a completed drop,
+// or nil if the drop is still in progress.
+func decodeDropEvent(ev *C.SDL_Event) Event {
+	e := (*C.SDL_DropEvent)(unsafe.Pointer(ev))
+	id := windowID(e.windowID)
+	var item string
+	if e.file != nil {
+		item = C.GoString(e.file)
+		C.SDL_free(unsafe.Pointer(e.file))
+	}
+	d, ok := pendingDrops[id]
+	switch e._type {
+	case C.SDL_DROPBEGIN:
+		// Drops onto no window, or onto a destroyed one, are not delivered,
+		// so they are not kept either.
+		if h := newEventHeader(e.windowID, e.timestamp); h.win != nil {
+			pendingDrops[id] = &DropEvent{eventHeader: h}
+		}
+		return nil
+	case C.SDL_DROPCOMPLETE:
+		delete(pendingDrops, id)
+		if !ok || len(d.Files)+len(d.Texts) == 0 {
+			return nil
+		}
+		return d
+	}
+	if !ok {
+		// Without a DROPBEGIN, each item is its own drop.
+		d = &DropEvent{eventHeader: newEventHeader(e.windowID, e.timestamp)}
+	}
+	if e._type == C.SDL_DROPFILE {
+		d.Files = append(d.Files, item)
+	} else {
+		d.Texts = append(d.Texts, item)
+	}
+	if ok {
+		return nil
+	}
+	return d
+}
+
+func (e *DropEvent) String() string {
+	return fmt.Sprintf("DropEvent{Files: %q, Texts: %q}", e.Files, e.Texts)
+}
+
+// A Button is a mouse button.
 type Button C.Uint8
 
+// The mouse buttons.
 const (
 	ButtonLeft   Button = C.SDL_BUTTON_LEFT
 	ButtonMiddle Button = C.SDL_BUTTON_MIDDLE
@@ -200,42 +679,73 @@ func (b Button) String() string {
 
 }
 
+// A MouseButtonEvent is a press or release of a mouse button.
 type MouseButtonEvent struct {
-	winID  windowID
+	eventHeader
 	Button Button
-	Down   bool
-	X, Y   int
-}
 
-func (e MouseButtonEvent) windowID() windowID {
-	return e.winID
+	// Down is true if the button was pressed, and false if it was released.
+	Down bool
+
+	// Clicks is the number of clicks in quick succession: 1 for a single click,
+	// 2 for a double click, and so on.
+	Clicks int
+
+	// X and Y are the mouse position relative to the window.
+	X, Y int
 }
 
 func newMouseButtonEvent(ev *C.SDL_Event) *MouseButtonEvent {
 	e := (*C.SDL_MouseButtonEvent)(unsafe.Pointer(ev))
 	return &MouseButtonEvent{
-		winID:  windowID(e.windowID),
-		Button: Button(e.button),
-		Down:   e.state == C.SDL_PRESSED,
-		X:      int(e.x),
-		Y:      int(e.y),
+		eventHeader: newEventHeader(e.windowID, e.timestamp),
+		Button:      Button(e.button),
+		Down:        e.state == C.SDL_PRESSED,
+		Clicks:      int(e.clicks),
+		X:           int(e.x),
+		Y:           int(e.y),
 	}
 }
 
+func (e *MouseButtonEvent) String() string {
+	return fmt.Sprintf("MouseButtonEvent{Button: %v, Down: %t, Clicks: %d, X: %d, Y: %d}",
+		e.Button, e.Down, e.Clicks, e.X, e.Y)
+}
+
+// A MouseWheelEvent is a movement of the mouse wheel.
 type MouseWheelEvent struct {
-	winID windowID
-	X, Y  int
+	eventHeader
+
+	// X and Y are the amounts scrolled horizontally and vertically,
+	// positive to the right and away from the user.
+	X, Y int
+
+	// PreciseX and PreciseY are the amounts scrolled with sub-step precision,
+	// as reported by high-resolution wheels and touchpads.
+	// With SDL before 2.0.18, they are the same as X and Y.
+	PreciseX, PreciseY float64
+
+	// Flipped is true if the system has inverted the scroll direction,
+	// such as for "natural" scrolling.  The amounts are as reported by the
+	// system; negate them to get the physical direction of the wheel.
+	Flipped bool
 }
 
 func newMouseWheelEvent(ev *C.SDL_Event) *MouseWheelEvent {
 	e := (*C.SDL_MouseWheelEvent)(unsafe.Pointer(ev))
+	var px, py C.float
+	C.wheelPrecise(e, &px, &py)
 	return &MouseWheelEvent{
-		winID: windowID(e.windowID),
-		X:     int(e.x),
-		Y:     int(e.y),
+		eventHeader: newEventHeader(e.windowID, e.timestamp),
+		X:           int(e.x),
+		Y:           int(e.y),
+		PreciseX:    float64(px),
+		PreciseY:    float64(py),
+		Flipped:     e.direction == C.SDL_MOUSEWHEEL_FLIPPED,
 	}
 }
 
-func (e MouseWheelEvent) windowID() windowID {
-	return e.winID
+func (e *MouseWheelEvent) String() string {
+	return fmt.Sprintf("MouseWheelEvent{X: %d, Y: %d, PreciseX: %g, PreciseY: %g, Flipped: %t}",
+		e.X, e.Y, e.PreciseX, e.PreciseY, e.Flipped)
 }
@@ -8,6 +8,7 @@ package ui
 import "C"
 
 import (
+	"errors"
 	"image"
 	"image/color"
 	"io/ioutil"
@@ -26,8 +27,13 @@ const (
 	ptInch = 72.0
 )
 
+// Fonts caches loaded fonts by path.
+// It is only accessed from the main go routine.
 var fonts = make(map[string]font)
 
+// ErrNoFont is returned when drawing text on a Canvas that has no font set.
+var ErrNoFont = errors.New("ui: no font set")
+
 type font struct {
 	size int
 	path string
@@ -35,23 +41,43 @@ type font struct {
 	*freetype.Context
 }
 
-func getFont(path string, sizePts int) font {
-	sizePx := int(float64(sizePts)/ptInch*pxInch + 0.5)
+// LoadFont loads a TrueType font file, caching it for later use by Canvas.SetFont.
+// Errors are reported as a *LoadError.
+// It returns ErrNotRunning if the user interface is not running.
+func LoadFont(path string) error {
+	return doErr(func() error {
+		_, err := loadFont(path)
+		return err
+	})
+}
 
+// GetFont returns the font loaded from path, at the given size in points.
+// It must be called from the main go routine.
+func getFont(path string, sizePts int) (font, error) {
+	f, err := loadFont(path)
+	if err != nil {
+		return font{}, err
+	}
+	f.size = int(float64(sizePts)/ptInch*pxInch + 0.5)
+	return f, nil
+}
+
+// LoadFont returns the font loaded from path, loading it if it is not cached.
+// It must be called from the main go routine.
+func loadFont(path string) (font, error) {
 	if f, ok := fonts[path]; ok {
-		f.size = sizePx
-		return f
+		return f, nil
 	}
 
 	in, err := os.Open(path)
 	if err != nil {
-		panic(err)
+		return font{}, &LoadError{Path: path, Err: err}
 	}
 	defer in.Close()
 
 	fdata, err := ioutil.ReadAll(in)
 	if err != nil {
-		panic(err)
+		return font{}, &LoadError{Path: path, Err: err}
 	}
 
 	f := font{
@@ -59,14 +85,12 @@ func getFont(path string, sizePts int) font {
 		Context: freetype.NewContext(),
 	}
 	if f.Font, err = truetype.Parse(fdata); err != nil {
-		panic(err)
+		return font{}, &LoadError{Path: path, Err: err}
 	}
 	f.SetFont(f.Font)
 	f.SetDPI(pxInch)
 	fonts[path] = f
-
-	f.size = sizePx
-	return f
+	return f, nil
 }
 
 func (f *font) extents() (height, ascent, descent int) {
@@ -97,7 +121,7 @@ func (f *font) scale() float64 {
 	return (float64(f.size) / ptInch * pxInch) / float64(em)
 }
 
-func (f *font) draw(s string, col color.Color) *image.NRGBA {
+func (f *font) draw(s string, col color.Color) (*image.NRGBA, error) {
 	width := f.width(s)
 	height, _, descent := f.extents()
 	img := image.NewNRGBA(image.Rect(0, 0, width, height))
@@ -106,7 +130,7 @@ func (f *font) draw(s string, col color.Color) *image.NRGBA {
 	f.SetClip(img.Bounds())
 	f.SetDst(img)
 	if _, err := f.DrawString(s, freetype.Pt(0, height+descent)); err != nil {
-		panic(err)
+		return nil, err
 	}
-	return img
+	return img, nil
 }
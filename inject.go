@@ -0,0 +1,266 @@
+// © 2013 the Ui Authors under the MIT license. See AUTHORS for the list of authors.
+
+package ui
+
+/*
+#include "ui.h"
+*/
+import "C"
+
+import (
+	"errors"
+	"strings"
+	"unsafe"
+)
+
+// ErrCannotInject is returned by Window.Inject for events that cannot be injected.
+var ErrCannotInject = errors.New("ui: event cannot be injected")
+
+// Inject pushes an event onto SDL's event queue, addressed to the window.
+// The event is decoded and delivered as if it came from the system: window
+// events on the window's event channel, and application events, such as
+// QuitEvent and the controller events, on the channel returned by Events.
+// Inject is intended for testing.
+//
+// The event's Window and Timestamp are ignored; the event is delivered to
+// this window with the time at which it was injected.  Fields that SDL
+// derives are also ignored: the window coordinates of touch events are
+// computed from the normalized ones.  A MouseButtonEvent with zero Clicks
+// is injected as a single click, and a MouseWheelEvent with zero precise
+// amounts has them set from X and Y.  A KeyboardEvent with only one of Key
+// or Scancode set has the other filled in from the current keyboard layout.
+//
+// Inject returns ErrCannotInject for events that cannot be represented
+// as an SDL event, such as text input longer than 31 bytes.
+func (win *Window) Inject(ev Event) error {
+	return win.do(func() error {
+		if d, ok := ev.(*DropEvent); ok {
+			return win.injectDrop(d)
+		}
+		var sev C.SDL_Event
+		if !win.encodeEvent(&sev, ev) {
+			return ErrCannotInject
+		}
+		if err := pushSDLEvent(&sev); err != nil {
+			if _, ok := ev.(*UserEvent); ok {
+				// The value will never be delivered, so don't keep it.
+				userMu.Lock()
+				delete(userValues, (*C.SDL_UserEvent)(unsafe.Pointer(&sev)).code)
+				userMu.Unlock()
+			}
+			return err
+		}
+		return nil
+	})
+}
+
+// EncodeEvent sets sev to the SDL event for ev, addressed to the window.
+// It returns false if ev cannot be represented as an SDL event.
+// It must be called from the main go routine.
+func (win *Window) encodeEvent(sev *C.SDL_Event, ev Event) bool {
+	id := C.Uint32(win.id)
+	switch ev := ev.(type) {
+	case *WindowEvent:
+		e := (*C.SDL_WindowEvent)(unsafe.Pointer(sev))
+		e._type = C.SDL_WINDOWEVENT
+		e.windowID = id
+		e.event = C.Uint8(ev.Event)
+		e.data1, e.data2 = C.Sint32(ev.Data1), C.Sint32(ev.Data2)
+	case *KeyboardEvent:
+		e := (*C.SDL_KeyboardEvent)(unsafe.Pointer(sev))
+		e._type, e.state = eventState(ev.Down, C.SDL_KEYDOWN, C.SDL_KEYUP)
+		e.windowID = id
+		if ev.Repeat {
+			e.repeat = 1
+		}
+		key, code := ev.Key, ev.Scancode
+		if code == 0 {
+			code = key.Scancode()
+		} else if key == 0 {
+			key = code.Key()
+		}
+		e.keysym.sym = C.SDL_Keycode(key)
+		e.keysym.scancode = C.SDL_Scancode(code)
+		e.keysym.mod = C.Uint16(ev.Mod)
+	case *TextInputEvent:
+		e := (*C.SDL_TextInputEvent)(unsafe.Pointer(sev))
+		e._type = C.SDL_TEXTINPUT
+		e.windowID = id
+		if !setEventText(e.text[:], ev.Text) {
+			return false
+		}
+	case *TextEditingEvent:
+		e := (*C.SDL_TextEditingEvent)(unsafe.Pointer(sev))
+		e._type = C.SDL_TEXTEDITING
+		e.windowID = id
+		e.start, e.length = C.Sint32(ev.Start), C.Sint32(ev.Length)
+		if !setEventText(e.text[:], ev.Text) {
+			return false
+		}
+	case *MouseMotionEvent:
+		e := (*C.SDL_MouseMotionEvent)(unsafe.Pointer(sev))
+		e._type = C.SDL_MOUSEMOTION
+		e.windowID = id
+		e.x, e.y = C.Sint32(ev.X), C.Sint32(ev.Y)
+		e.xrel, e.yrel = C.Sint32(ev.Xrel), C.Sint32(ev.Yrel)
+	case *MouseButtonEvent:
+		e := (*C.SDL_MouseButtonEvent)(unsafe.Pointer(sev))
+		e._type, e.state = eventState(ev.Down, C.SDL_MOUSEBUTTONDOWN, C.SDL_MOUSEBUTTONUP)
+		e.windowID = id
+		e.button = C.Uint8(ev.Button)
+		e.clicks = 1
+		if ev.Clicks > 0 {
+			e.clicks = C.Uint8(ev.Clicks)
+		}
+		e.x, e.y = C.Sint32(ev.X), C.Sint32(ev.Y)
+	case *MouseWheelEvent:
+		e := (*C.SDL_MouseWheelEvent)(unsafe.Pointer(sev))
+		e._type = C.SDL_MOUSEWHEEL
+		e.windowID = id
+		e.x, e.y = C.Sint32(ev.X), C.Sint32(ev.Y)
+		if ev.PreciseX == 0 && ev.PreciseY == 0 {
+			setWheelPrecise(e, float64(ev.X), float64(ev.Y))
+		} else {
+			setWheelPrecise(e, ev.PreciseX, ev.PreciseY)
+		}
+		e.direction = C.SDL_MOUSEWHEEL_NORMAL
+		if ev.Flipped {
+			e.direction = C.SDL_MOUSEWHEEL_FLIPPED
+		}
+	case *TouchEvent:
+		e := (*C.SDL_TouchFingerEvent)(unsafe.Pointer(sev))
+		e._type = C.Uint32(ev.Event)
+		e.touchId = C.SDL_TouchID(ev.TouchID)
+		e.fingerId = C.SDL_FingerID(ev.FingerID)
+		setTouchWindow(e, win)
+		e.x, e.y = C.float(ev.X), C.float(ev.Y)
+		e.dx, e.dy = C.float(ev.DX), C.float(ev.DY)
+		e.pressure = C.float(ev.Pressure)
+	case *MultiGestureEvent:
+		e := (*C.SDL_MultiGestureEvent)(unsafe.Pointer(sev))
+		e._type = C.SDL_MULTIGESTURE
+		e.touchId = C.SDL_TouchID(ev.TouchID)
+		e.dTheta, e.dDist = C.float(ev.DTheta), C.float(ev.DDist)
+		e.x, e.y = C.float(ev.X), C.float(ev.Y)
+		e.numFingers = C.Uint16(ev.NumFingers)
+		// Gestures go to the window of the device's most recent touch.
+		touchWindows[e.touchId] = win
+	case *ControllerDeviceEvent:
+		e := (*C.SDL_ControllerDeviceEvent)(unsafe.Pointer(sev))
+		e._type = C.Uint32(ev.Event)
+		e.which = C.Sint32(ev.ID)
+		if ev.Event == ControllerAdded {
+			e.which = C.Sint32(ev.Index)
+		}
+	case *ControllerButtonEvent:
+		e := (*C.SDL_ControllerButtonEvent)(unsafe.Pointer(sev))
+		e._type, e.state = eventState(ev.Down, C.SDL_CONTROLLERBUTTONDOWN, C.SDL_CONTROLLERBUTTONUP)
+		e.which = C.SDL_JoystickID(ev.ID)
+		e.button = C.Uint8(ev.Button)
+	case *ControllerAxisEvent:
+		e := (*C.SDL_ControllerAxisEvent)(unsafe.Pointer(sev))
+		e._type = C.SDL_CONTROLLERAXISMOTION
+		e.which = C.SDL_JoystickID(ev.ID)
+		e.axis = C.Uint8(ev.Axis)
+		e.value = C.Sint16(ev.Value)
+	case *UserEvent:
+		e := (*C.SDL_UserEvent)(unsafe.Pointer(sev))
+		e._type = userEvent
+		e.windowID = id
+		userMu.Lock()
+		e.code = nextUserCode
+		nextUserCode++
+		userValues[e.code] = ev.Value
+		userMu.Unlock()
+	case *QuitEvent:
+		(*C.SDL_QuitEvent)(unsafe.Pointer(sev))._type = C.SDL_QUIT
+	case *ClipboardUpdateEvent:
+		(*C.SDL_CommonEvent)(unsafe.Pointer(sev))._type = C.SDL_CLIPBOARDUPDATE
+	default:
+		return false
+	}
+	return true
+}
+
+// The types of the SDL drop events.
+const (
+	dropBegin    C.Uint32 = C.SDL_DROPBEGIN
+	dropFile     C.Uint32 = C.SDL_DROPFILE
+	dropText     C.Uint32 = C.SDL_DROPTEXT
+	dropComplete C.Uint32 = C.SDL_DROPCOMPLETE
+)
+
+// InjectDrop pushes the SDL events for a drop of all of the items of d onto the window.
+// If an item cannot be pushed, the drop is completed with the items pushed so far,
+// so that it is not left pending.
+// It must be called from the main go routine.
+func (win *Window) injectDrop(d *DropEvent) error {
+	if err := win.pushDrop(dropBegin, nil); err != nil {
+		return err
+	}
+	err := win.pushDropItems(dropFile, d.Files)
+	if err == nil {
+		err = win.pushDropItems(dropText, d.Texts)
+	}
+	if cerr := win.pushDrop(dropComplete, nil); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+// PushDropItems pushes a drop event of type t for each item.
+func (win *Window) pushDropItems(t C.Uint32, items []string) error {
+	for i := range items {
+		if err := win.pushDrop(t, &items[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// PushDrop pushes a single SDL drop event onto the window.
+// The item, if any, is copied with SDL_strdup, because it is freed with SDL_free when decoded.
+func (win *Window) pushDrop(t C.Uint32, item *string) error {
+	var sev C.SDL_Event
+	e := (*C.SDL_DropEvent)(unsafe.Pointer(&sev))
+	e._type = t
+	e.windowID = C.Uint32(win.id)
+	if item != nil {
+		citem := C.CString(*item)
+		e.file = C.SDL_strdup(citem)
+		C.free(unsafe.Pointer(citem))
+	}
+	if err := pushSDLEvent(&sev); err != nil {
+		C.SDL_free(unsafe.Pointer(e.file))
+		return err
+	}
+	return nil
+}
+
+// PushSDLEvent pushes an event onto SDL's event queue.
+func pushSDLEvent(sev *C.SDL_Event) error {
+	if C.SDL_PushEvent(sev) < 0 {
+		return sdlError("SDL_PushEvent")
+	}
+	return nil
+}
+
+// EventState returns the SDL event type and state for a press or release.
+func eventState(down bool, downType, upType C.Uint32) (C.Uint32, C.Uint8) {
+	if down {
+		return downType, C.SDL_PRESSED
+	}
+	return upType, C.SDL_RELEASED
+}
+
+// SetEventText copies s into the fixed-size text of an SDL event.
+// It returns false if s, with its terminating NUL, does not fit.
+func setEventText(dst []C.char, s string) bool {
+	if len(s) >= len(dst) || strings.IndexByte(s, 0) >= 0 {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		dst[i] = C.char(s[i])
+	}
+	return true
+}
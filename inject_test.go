@@ -0,0 +1,180 @@
+// © 2013 the Ui Authors under the MIT license. See AUTHORS for the list of authors.
+
+package ui_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/velour/ui"
+	"github.com/velour/ui/uitest"
+)
+
+// InjectTests has an injectable event of each type, and the event that should be delivered.
+var injectTests = []struct {
+	name string
+	in   ui.Event
+	want ui.Event
+	// Old, if non-nil, is the event delivered with SDL before 2.0.18.
+	old ui.Event
+}{
+	{name: "WindowEvent", in: &ui.WindowEvent{Event: ui.WindowMoved, Data1: 3, Data2: -4}},
+	{name: "KeyboardEvent", in: &ui.KeyboardEvent{Down: true, Repeat: true, Key: ui.KeyA, Scancode: ui.ScancodeA, Mod: ui.ModLShift}},
+	{
+		name: "KeyboardEvent without Scancode",
+		in:   &ui.KeyboardEvent{Down: true, Key: ui.KeyB},
+		want: &ui.KeyboardEvent{Down: true, Key: ui.KeyB, Scancode: ui.ScancodeB},
+	},
+	{
+		name: "KeyboardEvent without Key",
+		in:   &ui.KeyboardEvent{Scancode: ui.ScancodeC, Mod: ui.ModCtrl},
+		want: &ui.KeyboardEvent{Key: ui.KeyC, Scancode: ui.ScancodeC, Mod: ui.ModCtrl},
+	},
+	{name: "TextInputEvent", in: &ui.TextInputEvent{Text: "héllo"}},
+	{name: "TextInputEvent of 31 bytes", in: &ui.TextInputEvent{Text: strings.Repeat("x", 31)}},
+	{name: "TextEditingEvent", in: &ui.TextEditingEvent{Text: "ni", Start: 1, Length: 2}},
+	{name: "MouseMotionEvent", in: &ui.MouseMotionEvent{X: 10, Y: 12, Xrel: -1, Yrel: 2}},
+	{name: "MouseButtonEvent", in: &ui.MouseButtonEvent{Button: ui.ButtonRight, Down: true, Clicks: 2, X: 3, Y: 4}},
+	{
+		name: "MouseButtonEvent without Clicks",
+		in:   &ui.MouseButtonEvent{Button: ui.ButtonLeft, X: 5, Y: 6},
+		want: &ui.MouseButtonEvent{Button: ui.ButtonLeft, Clicks: 1, X: 5, Y: 6},
+	},
+	{
+		name: "MouseWheelEvent",
+		in:   &ui.MouseWheelEvent{X: 1, Y: -2, PreciseX: 0.5, PreciseY: -1.75, Flipped: true},
+		old:  &ui.MouseWheelEvent{X: 1, Y: -2, PreciseX: 1, PreciseY: -2, Flipped: true},
+	},
+	{
+		name: "MouseWheelEvent without precise amounts",
+		in:   &ui.MouseWheelEvent{X: 2, Y: -1},
+		want: &ui.MouseWheelEvent{X: 2, Y: -1, PreciseX: 2, PreciseY: -1},
+	},
+	{
+		name: "TouchEvent",
+		in:   &ui.TouchEvent{Event: ui.TouchDown, TouchID: 7, FingerID: 3, X: 0.25, Y: 0.5, DX: 0.125, DY: -0.0625, Pressure: 0.75},
+		want: &ui.TouchEvent{Event: ui.TouchDown, TouchID: 7, FingerID: 3, X: 0.25, Y: 0.5, DX: 0.125, DY: -0.0625, WinX: 8, WinY: 8, Pressure: 0.75},
+	},
+	{
+		name: "MultiGestureEvent",
+		in:   &ui.MultiGestureEvent{TouchID: 7, DTheta: 0.5, DDist: -0.25, X: 0.5, Y: 0.25, NumFingers: 2},
+		want: &ui.MultiGestureEvent{TouchID: 7, DTheta: 0.5, DDist: -0.25, X: 0.5, Y: 0.25, WinX: 16, WinY: 4, NumFingers: 2},
+	},
+	{name: "UserEvent", in: &ui.UserEvent{Value: []int{1, 2}}},
+	{name: "ControllerDeviceEvent", in: &ui.ControllerDeviceEvent{Event: ui.ControllerRemapped, Index: -1, ID: 42}},
+	{name: "ControllerButtonEvent", in: &ui.ControllerButtonEvent{ID: 42, Button: ui.ControllerButtonB, Down: true}},
+	{name: "ControllerAxisEvent", in: &ui.ControllerAxisEvent{ID: 42, Axis: ui.ControllerAxisLeftX, Value: -32768}},
+	{name: "QuitEvent", in: &ui.QuitEvent{}},
+	{name: "ClipboardUpdateEvent", in: &ui.ClipboardUpdateEvent{}},
+}
+
+// SameFields returns whether a and b are of the same type and have equal exported fields.
+func sameFields(a, b ui.Event) bool {
+	va, vb := reflect.ValueOf(a).Elem(), reflect.ValueOf(b).Elem()
+	if va.Type() != vb.Type() {
+		return false
+	}
+	for i := 0; i < va.NumField(); i++ {
+		if f := va.Type().Field(i); f.PkgPath != "" || f.Anonymous {
+			continue
+		}
+		if !reflect.DeepEqual(va.Field(i).Interface(), vb.Field(i).Interface()) {
+			return false
+		}
+	}
+	return true
+}
+
+// IsAppEvent returns whether ev is delivered on the channel returned by ui.Events.
+func isAppEvent(ev ui.Event) bool {
+	switch ev.(type) {
+	case *ui.QuitEvent, *ui.ClipboardUpdateEvent, *ui.ControllerDeviceEvent, *ui.ControllerButtonEvent, *ui.ControllerAxisEvent:
+		return true
+	}
+	return false
+}
+
+// NextOf returns the next event of the same type as want delivered to the window,
+// or, for application events, on the channel returned by ui.Events.
+// Events of other types, such as those sent by SDL when a window is shown, are skipped.
+// NextOf returns nil if no such event arrives within a second.
+func nextOf(win *ui.Window, want ui.Event) ui.Event {
+	deadline := time.Now().Add(time.Second)
+	for {
+		var ev ui.Event
+		if isAppEvent(want) {
+			select {
+			case ev = <-ui.Events():
+			case <-time.After(time.Until(deadline)):
+			}
+		} else {
+			ev = uitest.Next(win, time.Until(deadline))
+		}
+		if ev == nil || reflect.TypeOf(ev) == reflect.TypeOf(want) {
+			return ev
+		}
+	}
+}
+
+func TestInject(t *testing.T) {
+	win, err := ui.NewWindowErr("test", 32, 16)
+	if err != nil {
+		t.Fatalf("NewWindowErr: %v", err)
+	}
+	defer win.Destroy()
+
+	for _, test := range injectTests {
+		want := test.want
+		if want == nil {
+			want = test.in
+		}
+		if err := win.Inject(test.in); err != nil {
+			t.Errorf("%s: Inject: %v", test.name, err)
+			continue
+		}
+		got := nextOf(win, want)
+		if got == nil {
+			t.Errorf("%s: no event delivered", test.name)
+			continue
+		}
+		if !sameFields(got, want) && (test.old == nil || !sameFields(got, test.old)) {
+			t.Errorf("%s: got %v, want %v", test.name, got, want)
+		}
+		if app := isAppEvent(got); app && got.Window() != nil {
+			t.Errorf("%s: Window() = %v, want nil", test.name, got.Window())
+		} else if !app && got.Window() != win {
+			t.Errorf("%s: Window() = %v, want the injecting window", test.name, got.Window())
+		}
+	}
+}
+
+// An otherEvent is an event type unknown to package ui.
+type otherEvent struct{}
+
+func (otherEvent) Window() *ui.Window       { return nil }
+func (otherEvent) Timestamp() time.Duration { return 0 }
+func (otherEvent) String() string           { return "otherEvent{}" }
+
+func TestInjectError(t *testing.T) {
+	win, err := ui.NewWindowErr("test", 8, 8)
+	if err != nil {
+		t.Fatalf("NewWindowErr: %v", err)
+	}
+	for _, ev := range []ui.Event{
+		&ui.TextInputEvent{Text: strings.Repeat("x", 32)},
+		&ui.TextEditingEvent{Text: strings.Repeat("é", 16)},
+		&ui.TextInputEvent{Text: "a\x00b"},
+		otherEvent{},
+	} {
+		if err := win.Inject(ev); err != ui.ErrCannotInject {
+			t.Errorf("Inject(%v) = %v, want ErrCannotInject", ev, err)
+		}
+	}
+
+	win.Destroy()
+	if err := win.Inject(&ui.KeyboardEvent{Key: ui.KeyA}); err != ui.ErrDestroyed {
+		t.Errorf("Inject after Destroy = %v, want ErrDestroyed", err)
+	}
+}
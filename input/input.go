@@ -0,0 +1,564 @@
+// © 2013 the Ui Authors under the MIT license. See AUTHORS for the list of authors.
+
+// Package input maps physical inputs, such as keys, mouse buttons and
+// game controller buttons, to named actions, such as "jump" or "save".
+//
+// A Map is fed the events from a window's event channel, and, for game
+// controllers, from the application's event channel.  It tracks
+// whether each action is pressed, held or released in the current frame:
+//
+//	m := input.NewMap()
+//	m.Bind("jump", input.Key(ui.KeySpace, 0), input.MouseButton(ui.ButtonLeft))
+//	m.Bind("save", input.Key(ui.KeyS, ui.ModCtrl))
+//	for {
+//		select {
+//		case ev := <-win.Events():
+//			m.Handle(ev)
+//		case ev := <-ui.Events():
+//			m.Handle(ev)
+//		case <-tick.C:
+//			if m.Pressed("jump") {
+//				...
+//			}
+//			m.NextFrame()
+//		}
+//	}
+//
+// Bindings can be changed at any time, and saved to and loaded from JSON.
+package input
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/velour/ui"
+)
+
+// A Kind is a kind of physical input.
+type Kind int
+
+// The kinds of physical input.
+const (
+	KindKey Kind = iota + 1
+	KindMouseButton
+	KindWheel
+	KindGamepadButton
+)
+
+// A WheelDirection is a direction that the mouse wheel can be scrolled.
+type WheelDirection int
+
+// The directions of the mouse wheel.
+const (
+	WheelUp WheelDirection = iota + 1
+	WheelDown
+	WheelLeft
+	WheelRight
+)
+
+var wheelNames = map[WheelDirection]string{
+	WheelUp:    "Up",
+	WheelDown:  "Down",
+	WheelLeft:  "Left",
+	WheelRight: "Right",
+}
+
+func (d WheelDirection) String() string {
+	if n, ok := wheelNames[d]; ok {
+		return n
+	}
+	return "Unknown(" + strconv.Itoa(int(d)) + ")"
+}
+
+var buttonNames = map[ui.Button]string{
+	ui.ButtonLeft:   "Left",
+	ui.ButtonMiddle: "Middle",
+	ui.ButtonRight:  "Right",
+	ui.ButtonX1:     "X1",
+	ui.ButtonX2:     "X2",
+}
+
+// A Binding is a physical input that triggers an action.
+//
+// The string form of a Binding, returned by String and accepted by ParseBinding,
+// is the kind of input followed by a colon and the input, for example "Key:Ctrl+S",
+// "Mouse:Left", "Wheel:Up" or "Gamepad:a".  Gamepad buttons are named by
+// ui.ControllerButton.Name.  Binding implements
+// encoding.TextMarshaler and encoding.TextUnmarshaler using it.
+type Binding struct {
+	Kind Kind
+
+	// Key and Mod are the key and modifiers of a KindKey binding.
+	Key ui.Key
+	Mod ui.KeyMod
+
+	// Button is the mouse button of a KindMouseButton binding.
+	Button ui.Button
+
+	// Wheel is the wheel direction of a KindWheel binding.
+	Wheel WheelDirection
+
+	// GamepadButton is the game controller button of a KindGamepadButton binding.
+	GamepadButton ui.ControllerButton
+}
+
+// Key returns a binding for a key pressed with the given modifiers.
+// Left and right modifiers are not distinguished.
+//
+// A key press triggers the bindings with exactly its modifiers.  If there are none,
+// it triggers the bindings of the key without modifiers, so a binding with no
+// modifiers works while other modifiers are held, unless they form another binding.
+func Key(k ui.Key, mod ui.KeyMod) Binding {
+	return Binding{Kind: KindKey, Key: k, Mod: bindingMod(mod)}
+}
+
+// BindingMod returns the modifiers of mod that are meaningful for bindings,
+// with left and right modifiers not distinguished.
+func bindingMod(mod ui.KeyMod) ui.KeyMod {
+	var m ui.KeyMod
+	for _, bm := range []ui.KeyMod{ui.ModCtrl, ui.ModAlt, ui.ModShift, ui.ModGUI, ui.ModMode} {
+		if mod&bm != 0 {
+			m |= bm
+		}
+	}
+	return m
+}
+
+// MouseButton returns a binding for a mouse button.
+func MouseButton(b ui.Button) Binding {
+	return Binding{Kind: KindMouseButton, Button: b}
+}
+
+// Wheel returns a binding for scrolling the mouse wheel in a direction.
+// Scrolling presses and releases the action in the same frame.
+func Wheel(d WheelDirection) Binding {
+	return Binding{Kind: KindWheel, Wheel: d}
+}
+
+// GamepadButton returns a binding for a button on any open game controller.
+func GamepadButton(b ui.ControllerButton) Binding {
+	return Binding{Kind: KindGamepadButton, GamepadButton: b}
+}
+
+func (b Binding) String() string {
+	switch b.Kind {
+	case KindKey:
+		return "Key:" + ui.Shortcut{Key: b.Key, Mod: b.Mod}.String()
+	case KindMouseButton:
+		if n, ok := buttonNames[b.Button]; ok {
+			return "Mouse:" + n
+		}
+		return "Mouse:" + strconv.Itoa(int(b.Button))
+	case KindWheel:
+		return "Wheel:" + b.Wheel.String()
+	case KindGamepadButton:
+		return "Gamepad:" + b.GamepadButton.Name()
+	}
+	return "Unknown"
+}
+
+// ParseBinding parses a binding in the form returned by Binding.String.
+// It also accepts gamepad buttons by number, such as "Gamepad:0" for ui.ControllerButtonA.
+func ParseBinding(s string) (Binding, error) {
+	i := strings.IndexByte(s, ':')
+	if i < 0 {
+		return Binding{}, errors.New("input: malformed binding " + strconv.Quote(s))
+	}
+	kind, in := s[:i], s[i+1:]
+	switch kind {
+	case "Key":
+		k, mod, err := ui.ParseShortcut(in)
+		if err != nil {
+			return Binding{}, err
+		}
+		return Key(k, mod), nil
+	case "Mouse":
+		for b, n := range buttonNames {
+			if n == in {
+				return MouseButton(b), nil
+			}
+		}
+		if n, err := strconv.Atoi(in); err == nil {
+			return MouseButton(ui.Button(n)), nil
+		}
+	case "Wheel":
+		for d, n := range wheelNames {
+			if n == in {
+				return Wheel(d), nil
+			}
+		}
+	case "Gamepad":
+		// Earlier versions stored gamepad buttons by number, such as "Gamepad:0".
+		if n, err := strconv.Atoi(in); err == nil {
+			return GamepadButton(ui.ControllerButton(n)), nil
+		}
+		b, err := ui.ParseControllerButton(in)
+		if err != nil {
+			return Binding{}, err
+		}
+		return GamepadButton(b), nil
+	}
+	return Binding{}, errors.New("input: unknown binding " + strconv.Quote(s))
+}
+
+// MarshalText implements encoding.TextMarshaler.
+// It returns an error if ParseBinding could not parse the result,
+// such as for a key without a name.
+func (b Binding) MarshalText() ([]byte, error) {
+	s := b.String()
+	if _, err := ParseBinding(s); err != nil {
+		return nil, errors.New("input: cannot marshal binding " + strconv.Quote(s) + ": " + err.Error())
+	}
+	return []byte(s), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (b *Binding) UnmarshalText(text []byte) error {
+	nb, err := ParseBinding(string(text))
+	if err != nil {
+		return err
+	}
+	*b = nb
+	return nil
+}
+
+// BindingFor returns the binding triggered by an event, if any.
+// It is useful for letting the user choose a binding by pressing it.
+// Key releases and repeats, and button releases, have no binding.
+func BindingFor(ev ui.Event) (Binding, bool) {
+	switch ev := ev.(type) {
+	case *ui.KeyboardEvent:
+		if ev.Down && !ev.Repeat {
+			return Key(ev.Key, ev.Mod), true
+		}
+	case *ui.MouseButtonEvent:
+		if ev.Down {
+			return MouseButton(ev.Button), true
+		}
+	case *ui.MouseWheelEvent:
+		if d, ok := wheelDirection(ev); ok {
+			return Wheel(d), true
+		}
+	case *ui.ControllerButtonEvent:
+		if ev.Down {
+			return GamepadButton(ev.Button), true
+		}
+	}
+	return Binding{}, false
+}
+
+func wheelDirection(ev *ui.MouseWheelEvent) (WheelDirection, bool) {
+	switch {
+	case ev.Y > 0:
+		return WheelUp, true
+	case ev.Y < 0:
+		return WheelDown, true
+	case ev.X > 0:
+		return WheelRight, true
+	case ev.X < 0:
+		return WheelLeft, true
+	}
+	return 0, false
+}
+
+// A Map maps bindings to named actions, and tracks the state of each action.
+// It is safe to use a Map from multiple go routines.
+type Map struct {
+	mu       sync.Mutex
+	bindings map[string][]Binding
+	down     map[Binding]bool
+	states   map[string]*state
+}
+
+type state struct {
+	pressed, released bool
+}
+
+// NewMap returns a new, empty Map.
+func NewMap() *Map {
+	return &Map{
+		bindings: make(map[string][]Binding),
+		down:     make(map[Binding]bool),
+		states:   make(map[string]*state),
+	}
+}
+
+// Bind adds bindings to an action.
+func (m *Map) Bind(action string, bs ...Binding) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, b := range bs {
+		if !m.bound(action, b) {
+			m.bindings[action] = append(m.bindings[action], b)
+		}
+	}
+}
+
+func (m *Map) bound(action string, b Binding) bool {
+	for _, ab := range m.bindings[action] {
+		if ab == b {
+			return true
+		}
+	}
+	return false
+}
+
+// Unbind removes a binding from an action.
+func (m *Map) Unbind(action string, b Binding) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	bs := m.bindings[action]
+	for i, ab := range bs {
+		if ab == b {
+			m.bindings[action] = append(bs[:i:i], bs[i+1:]...)
+			break
+		}
+	}
+}
+
+// SetBindings replaces the bindings of an action.
+// With no bindings, the action is removed.
+func (m *Map) SetBindings(action string, bs ...Binding) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if len(bs) == 0 {
+		delete(m.bindings, action)
+		delete(m.states, action)
+		return
+	}
+	m.bindings[action] = nil
+	for _, b := range bs {
+		if !m.bound(action, b) {
+			m.bindings[action] = append(m.bindings[action], b)
+		}
+	}
+}
+
+// Bindings returns the bindings of an action.
+func (m *Map) Bindings(action string) []Binding {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]Binding(nil), m.bindings[action]...)
+}
+
+// Actions returns the names of all actions with bindings, in sorted order.
+func (m *Map) Actions() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var actions []string
+	for a, bs := range m.bindings {
+		if len(bs) > 0 {
+			actions = append(actions, a)
+		}
+	}
+	sort.Strings(actions)
+	return actions
+}
+
+// Handle updates the state of the actions from an event,
+// and returns whether the event triggered any binding.
+func (m *Map) Handle(ev ui.Event) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	switch ev := ev.(type) {
+	case *ui.KeyboardEvent:
+		if ev.Repeat {
+			return false
+		}
+		if ev.Down {
+			b := Key(ev.Key, ev.Mod)
+			if m.press(b) {
+				return true
+			}
+			// Fall back to the key's binding without modifiers, so that, say,
+			// a jump on Space still works while a sprint on Shift is held.
+			return b.Mod != 0 && m.press(Key(ev.Key, 0))
+		}
+		// Release the key regardless of the modifiers now down.
+		return m.release(func(b Binding) bool {
+			return b.Kind == KindKey && b.Key == ev.Key
+		})
+	case *ui.MouseButtonEvent:
+		b := MouseButton(ev.Button)
+		if ev.Down {
+			return m.press(b)
+		}
+		return m.release(func(db Binding) bool { return db == b })
+	case *ui.MouseWheelEvent:
+		d, ok := wheelDirection(ev)
+		if !ok {
+			return false
+		}
+		b := Wheel(d)
+		pressed := m.press(b)
+		m.release(func(db Binding) bool { return db == b })
+		return pressed
+	case *ui.ControllerButtonEvent:
+		b := GamepadButton(ev.Button)
+		if ev.Down {
+			return m.press(b)
+		}
+		return m.release(func(db Binding) bool { return db == b })
+	}
+	return false
+}
+
+// Press marks a binding as down, and returns whether it is bound to any action.
+func (m *Map) press(b Binding) bool {
+	matched := false
+	for action, bs := range m.bindings {
+		for _, ab := range bs {
+			if ab != b {
+				continue
+			}
+			matched = true
+			if !m.held(action) {
+				m.state(action).pressed = true
+			}
+		}
+	}
+	if matched {
+		m.down[b] = true
+	}
+	return matched
+}
+
+// Release marks the down bindings for which f returns true as up,
+// and returns whether any were down.
+func (m *Map) release(f func(Binding) bool) bool {
+	var released []Binding
+	for b := range m.down {
+		if f(b) {
+			released = append(released, b)
+		}
+	}
+	if len(released) == 0 {
+		return false
+	}
+	var wasHeld []string
+	for action := range m.bindings {
+		if m.held(action) {
+			wasHeld = append(wasHeld, action)
+		}
+	}
+	for _, b := range released {
+		delete(m.down, b)
+	}
+	for _, action := range wasHeld {
+		if !m.held(action) {
+			m.state(action).released = true
+		}
+	}
+	return true
+}
+
+func (m *Map) held(action string) bool {
+	for _, b := range m.bindings[action] {
+		if m.down[b] {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *Map) state(action string) *state {
+	s, ok := m.states[action]
+	if !ok {
+		s = &state{}
+		m.states[action] = s
+	}
+	return s
+}
+
+// Pressed returns whether the action was pressed during the current frame.
+func (m *Map) Pressed(action string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	s, ok := m.states[action]
+	return ok && s.pressed
+}
+
+// Held returns whether any binding of the action is down.
+func (m *Map) Held(action string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.held(action)
+}
+
+// Released returns whether the action was released during the current frame.
+func (m *Map) Released(action string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	s, ok := m.states[action]
+	return ok && s.released
+}
+
+// NextFrame ends the current frame, clearing the pressed and released states
+// of all actions.  It should be called once per frame, after the states are read.
+func (m *Map) NextFrame() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, s := range m.states {
+		s.pressed, s.released = false, false
+	}
+}
+
+// Reset clears the state of all actions, as if all inputs were released
+// without reporting them as released.  It is useful when the window loses focus.
+func (m *Map) Reset() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.down = make(map[Binding]bool)
+	m.states = make(map[string]*state)
+}
+
+// MarshalJSON implements json.Marshaler.
+// The JSON form is an object mapping each action to a list of bindings.
+func (m *Map) MarshalJSON() ([]byte, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return json.Marshal(m.bindings)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+// It replaces all bindings of the map, and resets the state of all actions.
+func (m *Map) UnmarshalJSON(data []byte) error {
+	var bindings map[string][]Binding
+	if err := json.Unmarshal(data, &bindings); err != nil {
+		return err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.bindings = make(map[string][]Binding)
+	for action, bs := range bindings {
+		for _, b := range bs {
+			if !m.bound(action, b) {
+				m.bindings[action] = append(m.bindings[action], b)
+			}
+		}
+	}
+	m.down = make(map[Binding]bool)
+	m.states = make(map[string]*state)
+	return nil
+}
+
+// Save writes the bindings to w as JSON.
+// It returns an error if a binding cannot be marshaled, such as a key without a name.
+func (m *Map) Save(w io.Writer) error {
+	data, err := json.MarshalIndent(m, "", "\t")
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(append(data, '\n'))
+	return err
+}
+
+// Load replaces the bindings with those read from r as JSON, in the form written by Save.
+func (m *Map) Load(r io.Reader) error {
+	return json.NewDecoder(r).Decode(m)
+}
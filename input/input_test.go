@@ -0,0 +1,263 @@
+// © 2013 the Ui Authors under the MIT license. See AUTHORS for the list of authors.
+
+package input
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/velour/ui"
+	"github.com/velour/ui/uitest"
+)
+
+// Key names come from SDL's keymap, so the tests need the user interface running.
+func TestMain(m *testing.M) {
+	uitest.Main(m)
+}
+
+func keyDown(k ui.Key, mod ui.KeyMod) *ui.KeyboardEvent {
+	return &ui.KeyboardEvent{Down: true, Key: k, Mod: mod}
+}
+
+func keyUp(k ui.Key, mod ui.KeyMod) *ui.KeyboardEvent {
+	return &ui.KeyboardEvent{Down: false, Key: k, Mod: mod}
+}
+
+// CheckState reports an error if the action's pressed, held and released states are not as wanted.
+func checkState(t *testing.T, m *Map, when, action string, pressed, held, released bool) {
+	t.Helper()
+	p, h, r := m.Pressed(action), m.Held(action), m.Released(action)
+	if p != pressed || h != held || r != released {
+		t.Errorf("%s: %s pressed, held, released = %t, %t, %t, want %t, %t, %t",
+			when, action, p, h, r, pressed, held, released)
+	}
+}
+
+func TestFrames(t *testing.T) {
+	m := NewMap()
+	m.Bind("jump", Key(ui.KeySpace, 0))
+	checkState(t, m, "initially", "jump", false, false, false)
+
+	if !m.Handle(keyDown(ui.KeySpace, 0)) {
+		t.Errorf("Handle(Space down) = false, want true")
+	}
+	checkState(t, m, "after press", "jump", true, true, false)
+	m.NextFrame()
+	checkState(t, m, "next frame", "jump", false, true, false)
+
+	m.Handle(&ui.KeyboardEvent{Down: true, Repeat: true, Key: ui.KeySpace})
+	checkState(t, m, "after repeat", "jump", false, true, false)
+
+	if !m.Handle(keyUp(ui.KeySpace, 0)) {
+		t.Errorf("Handle(Space up) = false, want true")
+	}
+	checkState(t, m, "after release", "jump", false, false, true)
+	m.NextFrame()
+	checkState(t, m, "next frame", "jump", false, false, false)
+
+	m.Handle(keyDown(ui.KeySpace, 0))
+	m.Handle(keyUp(ui.KeySpace, 0))
+	checkState(t, m, "press and release in one frame", "jump", true, false, true)
+
+	if m.Handle(keyDown(ui.KeyA, 0)) {
+		t.Errorf("Handle(unbound key) = true, want false")
+	}
+}
+
+func TestMultipleBindings(t *testing.T) {
+	m := NewMap()
+	m.Bind("fire", Key(ui.KeyF, 0), MouseButton(ui.ButtonLeft))
+
+	m.Handle(keyDown(ui.KeyF, 0))
+	m.NextFrame()
+	m.Handle(&ui.MouseButtonEvent{Button: ui.ButtonLeft, Down: true})
+	checkState(t, m, "second binding pressed while held", "fire", false, true, false)
+
+	m.Handle(keyUp(ui.KeyF, 0))
+	checkState(t, m, "one binding released", "fire", false, true, false)
+
+	m.Handle(&ui.MouseButtonEvent{Button: ui.ButtonLeft, Down: false})
+	checkState(t, m, "both bindings released", "fire", false, false, true)
+}
+
+func TestModifiers(t *testing.T) {
+	m := NewMap()
+	m.Bind("jump", Key(ui.KeySpace, 0))
+	m.Bind("sprint", Key(ui.KeyLShift, 0))
+	m.Bind("save", Key(ui.KeyS, ui.ModCtrl))
+	m.Bind("back", Key(ui.KeyS, 0))
+
+	// SDL reports a modifier key's own modifier as down when it is pressed.
+	m.Handle(keyDown(ui.KeyLShift, ui.ModLShift))
+	checkState(t, m, "Shift", "sprint", true, true, false)
+	m.Handle(keyDown(ui.KeySpace, ui.ModLShift))
+	checkState(t, m, "Space with Shift held", "jump", true, true, false)
+
+	m.Handle(keyUp(ui.KeyLShift, 0))
+	checkState(t, m, "Shift released", "sprint", true, false, true)
+	checkState(t, m, "Shift released", "jump", true, true, false)
+	m.Handle(keyUp(ui.KeySpace, 0))
+	checkState(t, m, "Space released without Shift", "jump", true, false, true)
+
+	m.NextFrame()
+	m.Handle(keyDown(ui.KeyS, ui.ModRCtrl))
+	checkState(t, m, "Ctrl+S", "save", true, true, false)
+	checkState(t, m, "Ctrl+S", "back", false, false, false)
+	m.Handle(keyUp(ui.KeyS, ui.ModRCtrl))
+
+	m.NextFrame()
+	m.Handle(keyDown(ui.KeyS, ui.ModAlt))
+	checkState(t, m, "Alt+S", "back", true, true, false)
+	checkState(t, m, "Alt+S", "save", false, false, false)
+}
+
+func TestWheelAndGamepad(t *testing.T) {
+	m := NewMap()
+	m.Bind("zoom", Wheel(WheelUp))
+	m.Bind("pause", GamepadButton(ui.ControllerButtonStart))
+
+	m.Handle(&ui.MouseWheelEvent{Y: 1})
+	checkState(t, m, "wheel up", "zoom", true, false, true)
+	m.NextFrame()
+	if m.Handle(&ui.MouseWheelEvent{Y: -1}) {
+		t.Errorf("Handle(wheel down) = true, want false")
+	}
+	checkState(t, m, "wheel down", "zoom", false, false, false)
+
+	m.Handle(&ui.ControllerButtonEvent{Button: ui.ControllerButtonStart, Down: true})
+	checkState(t, m, "Start", "pause", true, true, false)
+	m.Handle(&ui.ControllerButtonEvent{Button: ui.ControllerButtonStart, Down: false})
+	checkState(t, m, "Start released", "pause", true, false, true)
+}
+
+func TestReset(t *testing.T) {
+	m := NewMap()
+	m.Bind("jump", Key(ui.KeySpace, 0))
+	m.Handle(keyDown(ui.KeySpace, 0))
+	m.Reset()
+	checkState(t, m, "after Reset", "jump", false, false, false)
+	if m.Handle(keyUp(ui.KeySpace, 0)) {
+		t.Errorf("Handle(release after Reset) = true, want false")
+	}
+	checkState(t, m, "release after Reset", "jump", false, false, false)
+}
+
+var testBindings = []Binding{
+	Key(ui.KeyS, ui.ModCtrl|ui.ModShift),
+	Key(ui.KeySpace, 0),
+	Key(ui.KeyKPPlus, ui.ModAlt),
+	MouseButton(ui.ButtonLeft),
+	MouseButton(ui.ButtonX2),
+	Wheel(WheelUp),
+	Wheel(WheelLeft),
+	GamepadButton(ui.ControllerButtonA),
+	GamepadButton(ui.ControllerButtonDPadUp),
+}
+
+func TestBindingString(t *testing.T) {
+	for _, b := range testBindings {
+		s := b.String()
+		got, err := ParseBinding(s)
+		if err != nil || got != b {
+			t.Errorf("ParseBinding(%q) = %v, %v, want %v", s, got, err, b)
+		}
+	}
+	for _, test := range []struct {
+		s    string
+		want Binding
+	}{
+		{"Key:Ctrl+S", Key(ui.KeyS, ui.ModCtrl)},
+		{"Mouse:Left", MouseButton(ui.ButtonLeft)},
+		{"Mouse:4", MouseButton(ui.ButtonX1)},
+		{"Wheel:Down", Wheel(WheelDown)},
+		{"Gamepad:a", GamepadButton(ui.ControllerButtonA)},
+		{"Gamepad:0", GamepadButton(ui.ControllerButtonA)},
+	} {
+		got, err := ParseBinding(test.s)
+		if err != nil || got != test.want {
+			t.Errorf("ParseBinding(%q) = %v, %v, want %v", test.s, got, err, test.want)
+		}
+	}
+	for _, s := range []string{"", "Key", "Key:", "Mouse:Top", "Wheel:Sideways", "Gamepad:z", "Joystick:1"} {
+		if b, err := ParseBinding(s); err == nil {
+			t.Errorf("ParseBinding(%q) = %v, want an error", s, b)
+		}
+	}
+}
+
+func TestSaveLoad(t *testing.T) {
+	m := NewMap()
+	m.Bind("save", testBindings[0])
+	m.Bind("jump", testBindings[1:4]...)
+	m.Bind("other", testBindings[4:]...)
+
+	var buf bytes.Buffer
+	if err := m.Save(&buf); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	saved := buf.String()
+
+	l := NewMap()
+	l.Bind("stale", Key(ui.KeyQ, 0))
+	if err := l.Load(&buf); err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if got, want := l.Actions(), []string{"jump", "other", "save"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Actions() after Load = %v, want %v", got, want)
+	}
+	for _, action := range m.Actions() {
+		if got, want := l.Bindings(action), m.Bindings(action); !reflect.DeepEqual(got, want) {
+			t.Errorf("Bindings(%q) after Load = %v, want %v", action, got, want)
+		}
+	}
+
+	// Saving the loaded map gives the same JSON.
+	buf.Reset()
+	if err := l.Save(&buf); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	if buf.String() != saved {
+		t.Errorf("Save after Load:\n%s\nwant:\n%s", buf.String(), saved)
+	}
+}
+
+func TestSaveUnnamed(t *testing.T) {
+	for _, b := range []Binding{
+		Key(ui.KeyUnknown, ui.ModCtrl),
+		GamepadButton(ui.ControllerButton(100)),
+		Wheel(WheelDirection(100)),
+		{},
+	} {
+		if text, err := b.MarshalText(); err == nil {
+			t.Errorf("MarshalText of %v = %q, want an error", b, text)
+		}
+	}
+
+	m := NewMap()
+	m.Bind("jump", Key(ui.KeySpace, 0), Key(ui.KeyUnknown, 0))
+	var buf bytes.Buffer
+	if err := m.Save(&buf); err == nil {
+		t.Errorf("Save of an unnamed key succeeded:\n%s", buf.String())
+	}
+}
+
+func TestLoadOldGamepad(t *testing.T) {
+	m := NewMap()
+	if err := m.Load(strings.NewReader(`{"pause": ["Gamepad:6"]}`)); err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	want := []Binding{GamepadButton(ui.ControllerButtonStart)}
+	if got := m.Bindings("pause"); !reflect.DeepEqual(got, want) {
+		t.Errorf("Bindings after Load = %v, want %v", got, want)
+	}
+}
+
+func TestLoadError(t *testing.T) {
+	m := NewMap()
+	m.Bind("jump", Key(ui.KeySpace, 0))
+	if err := m.Load(strings.NewReader(`{"jump": ["Key:NoSuchKey"]}`)); err == nil {
+		t.Errorf("Load of an unknown key succeeded")
+	}
+}
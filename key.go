@@ -912,7 +912,7 @@ type KeyMod C.SDL_Keymod
 
 const (
 	// ModNone means no modifier is applicable.
-	ModNone = C.KMOD_NONE
+	ModNone KeyMod = C.KMOD_NONE
 
 	// ModLShift means that the left Shift key is down.
 	ModLShift KeyMod = C.KMOD_LSHIFT
@@ -979,16 +979,22 @@ var keyModNames = map[KeyMod]string{
 	ModGUI:    "ModGUI",
 }
 
+// KeyModOrder is the order in which modifiers are listed by KeyMod.String.
+var keyModOrder = []KeyMod{
+	ModLShift, ModRShift, ModLCtrl, ModRCtrl, ModLAlt, ModRAlt, ModLGUI, ModRGUI,
+	ModNum, ModCaps, ModMode, ModCtrl, ModShift, ModAlt, ModGUI,
+}
+
 func (mod KeyMod) String() string {
 	s := ""
-	for m, n := range keyModNames {
+	for _, m := range keyModOrder {
 		if mod&m == 0 {
 			continue
 		}
 		if s != "" {
 			s += " | "
 		}
-		s += n
+		s += keyModNames[m]
 	}
 	if s == "" {
 		return keyModNames[ModNone]
@@ -0,0 +1,63 @@
+// © 2013 the Ui Authors under the MIT license. See AUTHORS for the list of authors.
+
+package ui
+
+/*
+#include "ui.h"
+*/
+import "C"
+
+import (
+	"unsafe"
+)
+
+// KeyboardState returns a snapshot of the state of the keyboard, indexed by Scancode.
+// An element is true if the key at that scancode is down.
+//
+// The state is updated as the user interface reads events from SDL, before they are
+// sent on the event channels, so it reflects every event read so far.  It can be ahead
+// of the KeyboardEvents that have been received from a window's event channel, by as
+// many events as the channel buffers.
+func KeyboardState() []bool {
+	var state []bool
+	do(func() {
+		var n C.int
+		p := C.SDL_GetKeyboardState(&n)
+		keys := (*[1 << 20]C.Uint8)(unsafe.Pointer(p))[:n:n]
+		state = make([]bool, n)
+		for i, k := range keys {
+			state[i] = k != 0
+		}
+	})
+	return state
+}
+
+// IsScancodeDown returns whether the key at the scancode is down.
+func IsScancodeDown(s Scancode) bool {
+	var down bool
+	do(func() {
+		var n C.int
+		p := C.SDL_GetKeyboardState(&n)
+		if uint(s) >= uint(n) {
+			return
+		}
+		keys := (*[1 << 20]C.Uint8)(unsafe.Pointer(p))[:n:n]
+		down = keys[s] != 0
+	})
+	return down
+}
+
+// IsKeyDown returns whether the key is down.
+func IsKeyDown(k Key) bool {
+	return IsScancodeDown(k.Scancode())
+}
+
+// ModState returns the modifier keys that are down.
+// Like KeyboardState, it can be ahead of the events received from the event channels.
+func ModState() KeyMod {
+	var mod KeyMod
+	do(func() {
+		mod = KeyMod(C.SDL_GetModState())
+	})
+	return mod
+}
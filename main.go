@@ -21,7 +21,7 @@ const (
 )
 
 func main() {
-	ui.Start(main2, 20*time.Millisecond)
+	ui.Start(main2)
 }
 
 func main2() {
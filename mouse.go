@@ -0,0 +1,101 @@
+// © 2013 the Ui Authors under the MIT license. See AUTHORS for the list of authors.
+
+package ui
+
+/*
+#include "ui.h"
+*/
+import "C"
+
+import "strings"
+
+// MouseButtons is a set of mouse buttons.
+type MouseButtons uint32
+
+// Has returns whether the set contains the button.
+func (m MouseButtons) Has(b Button) bool {
+	return b > 0 && m&(1<<(uint(b)-1)) != 0
+}
+
+func (m MouseButtons) String() string {
+	var names []string
+	for b := ButtonLeft; b <= ButtonX2; b++ {
+		if m.Has(b) {
+			names = append(names, b.String())
+		}
+	}
+	return "{" + strings.Join(names, ", ") + "}"
+}
+
+// MouseState returns the mouse position relative to the window with mouse
+// focus, and the buttons that are down.
+func MouseState() (x, y int, buttons MouseButtons) {
+	do(func() {
+		var cx, cy C.int
+		buttons = MouseButtons(C.SDL_GetMouseState(&cx, &cy))
+		x, y = int(cx), int(cy)
+	})
+	return x, y, buttons
+}
+
+// GlobalMouseState returns the mouse position relative to the desktop,
+// and the buttons that are down.  Unlike MouseState, it queries the system,
+// so it is accurate even while the mouse is outside of all windows.
+func GlobalMouseState() (x, y int, buttons MouseButtons) {
+	do(func() {
+		var cx, cy C.int
+		buttons = MouseButtons(C.SDL_GetGlobalMouseState(&cx, &cy))
+		x, y = int(cx), int(cy)
+	})
+	return x, y, buttons
+}
+
+// WarpMouse moves the mouse to x, y in the window.
+// It generates a MouseMotionEvent.
+func (win *Window) WarpMouse(x, y int) error {
+	return win.do(func() error {
+		C.SDL_WarpMouseInWindow(win.win, C.int(x), C.int(y))
+		return nil
+	})
+}
+
+// CaptureMouse sets whether the mouse is captured.  While the mouse is captured,
+// the window with mouse focus receives mouse events even when the mouse is outside
+// of it, such as while dragging.  The capture should be released when the drag ends.
+func CaptureMouse(on bool) error {
+	return doErr(func() error {
+		if C.SDL_CaptureMouse(sdlBool(on)) < 0 {
+			return sdlError("SDL_CaptureMouse")
+		}
+		return nil
+	})
+}
+
+// SetRelativeMouseMode sets whether relative mouse mode is enabled.
+// In relative mode, the cursor is hidden, the mouse is confined to the window
+// with focus, and MouseMotionEvents report motion with Xrel and Yrel even
+// when the mouse would be at the edge of the screen, as for camera controls.
+func SetRelativeMouseMode(on bool) error {
+	return doErr(func() error {
+		if C.SDL_SetRelativeMouseMode(sdlBool(on)) < 0 {
+			return sdlError("SDL_SetRelativeMouseMode")
+		}
+		return nil
+	})
+}
+
+// RelativeMouseMode returns whether relative mouse mode is enabled.
+func RelativeMouseMode() bool {
+	var on bool
+	do(func() {
+		on = C.SDL_GetRelativeMouseMode() == C.SDL_TRUE
+	})
+	return on
+}
+
+func sdlBool(b bool) C.SDL_bool {
+	if b {
+		return C.SDL_TRUE
+	}
+	return C.SDL_FALSE
+}
@@ -0,0 +1,90 @@
+// © 2013 the Ui Authors under the MIT license. See AUTHORS for the list of authors.
+
+package ui
+
+/*
+#include "ui.h"
+
+static int pushUserEvent(Uint32 type, Uint32 windowID, Sint32 code) {
+	SDL_Event ev;
+	SDL_zero(ev);
+	ev.user.type = type;
+	ev.user.windowID = windowID;
+	ev.user.code = code;
+	return SDL_PushEvent(&ev);
+}
+*/
+import "C"
+
+import (
+	"fmt"
+	"sync"
+	"unsafe"
+)
+
+var (
+	// UserMu protects userValues and nextUserCode.
+	userMu sync.Mutex
+
+	// UserValues maps the codes of posted SDL user events to their values.
+	userValues = make(map[C.Sint32]interface{})
+
+	// NextUserCode is the code of the next posted SDL user event.
+	nextUserCode C.Sint32
+)
+
+// A UserEvent is a value posted to a window with Window.Post.
+type UserEvent struct {
+	eventHeader
+
+	// Value is the posted value.
+	Value interface{}
+}
+
+func newUserEvent(ev *C.SDL_Event) *UserEvent {
+	e := (*C.SDL_UserEvent)(unsafe.Pointer(ev))
+	userMu.Lock()
+	v := userValues[e.code]
+	delete(userValues, e.code)
+	userMu.Unlock()
+	return &UserEvent{
+		eventHeader: newEventHeader(e.windowID, e.timestamp),
+		Value:       v,
+	}
+}
+
+func (e *UserEvent) String() string {
+	return fmt.Sprintf("UserEvent{Value: %v}", e.Value)
+}
+
+// Post delivers a UserEvent with the value v on the window's event channel.
+// It may be called from any go routine.  The event goes through SDL's event
+// queue, so it is delivered in order with the input events that precede and
+// follow it, and it wakes the main loop.
+//
+// Post returns ErrDestroyed if the window has been destroyed,
+// and ErrNotRunning if the user interface is not running.
+func (win *Window) Post(v interface{}) error {
+	select {
+	case <-win.done:
+		return ErrDestroyed
+	default:
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if !running {
+		return ErrNotRunning
+	}
+	userMu.Lock()
+	code := nextUserCode
+	nextUserCode++
+	userValues[code] = v
+	userMu.Unlock()
+	if C.pushUserEvent(userEvent, C.Uint32(win.id), code) < 0 {
+		userMu.Lock()
+		delete(userValues, code)
+		userMu.Unlock()
+		return sdlError("SDL_PushEvent")
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+// © 2013 the Ui Authors under the MIT license. See AUTHORS for the list of authors.
+
+package ui_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/velour/ui"
+	"github.com/velour/ui/uitest"
+)
+
+type postValue struct {
+	s []string
+}
+
+func TestPostOrder(t *testing.T) {
+	win, err := ui.NewWindowErr("test", 8, 8)
+	if err != nil {
+		t.Fatalf("NewWindowErr: %v", err)
+	}
+	defer win.Destroy()
+
+	v := &postValue{s: []string{"x"}}
+	steps := []func() error{
+		func() error { return win.Inject(&ui.KeyboardEvent{Down: true, Key: ui.KeyA}) },
+		func() error { return win.Post("one") },
+		func() error { return win.Inject(&ui.KeyboardEvent{Down: false, Key: ui.KeyA}) },
+		func() error { return win.Post(v) },
+		func() error { return win.Post(nil) },
+		func() error { return win.Inject(&ui.KeyboardEvent{Down: true, Key: ui.KeyB}) },
+	}
+	for i, step := range steps {
+		if err := step(); err != nil {
+			t.Fatalf("step %d: %v", i, err)
+		}
+	}
+
+	var got []ui.Event
+	for len(got) < len(steps) {
+		ev := uitest.Next(win, time.Second)
+		if ev == nil {
+			t.Fatalf("got %d events %v, want %d", len(got), got, len(steps))
+		}
+		if _, ok := ev.(*ui.WindowEvent); !ok {
+			got = append(got, ev)
+		}
+	}
+	check := func(i int, ok bool) {
+		if !ok {
+			t.Errorf("event %d is %v", i, got[i])
+		}
+	}
+	k, ok := got[0].(*ui.KeyboardEvent)
+	check(0, ok && k.Key == ui.KeyA && k.Down)
+	u, ok := got[1].(*ui.UserEvent)
+	check(1, ok && u.Value == "one" && u.Window() == win)
+	k, ok = got[2].(*ui.KeyboardEvent)
+	check(2, ok && k.Key == ui.KeyA && !k.Down)
+	u, ok = got[3].(*ui.UserEvent)
+	check(3, ok && u.Value == v)
+	u, ok = got[4].(*ui.UserEvent)
+	check(4, ok && u.Value == nil)
+	k, ok = got[5].(*ui.KeyboardEvent)
+	check(5, ok && k.Key == ui.KeyB)
+}
+
+func TestPostDestroyed(t *testing.T) {
+	win, err := ui.NewWindowErr("test", 8, 8)
+	if err != nil {
+		t.Fatalf("NewWindowErr: %v", err)
+	}
+	if err := win.Destroy(); err != nil {
+		t.Fatalf("Destroy: %v", err)
+	}
+	if err := win.Post(1); err != ui.ErrDestroyed {
+		t.Errorf("Post after Destroy = %v, want ErrDestroyed", err)
+	}
+}
@@ -0,0 +1,139 @@
+// © 2013 the Ui Authors under the MIT license. See AUTHORS for the list of authors.
+
+package ui
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+const (
+	eventChanSize = 100
+
+	defaultBlockTimeout = 100 * time.Millisecond
+)
+
+// An OverflowPolicy says what happens to an event that arrives when its event channel is full.
+type OverflowPolicy int
+
+const (
+	// DropNewest discards the arriving event.
+	DropNewest OverflowPolicy = iota
+
+	// DropOldest discards the oldest event in the channel to make room for the arriving event.
+	DropOldest
+
+	// Block waits for room in the channel, for up to the BlockTimeout, and then
+	// discards the arriving event.  While it waits, the user interface handles
+	// no other events and no drawing.
+	Block
+)
+
+// EventOptions configure the buffering of an event channel.
+type EventOptions struct {
+	// BufferSize is the capacity of the event channel.
+	// If BufferSize is zero, a capacity of 100 is used.
+	BufferSize int
+
+	// Overflow says what happens to events that arrive when the channel is full.
+	Overflow OverflowPolicy
+
+	// BlockTimeout is the longest that the Block policy waits for room in the channel.
+	// If BlockTimeout is zero, 100 milliseconds is used.
+	BlockTimeout time.Duration
+
+	// CoalesceMotion merges bursts of MouseMotionEvents that arrive together into
+	// a single event with the final position and the total relative motion.
+	CoalesceMotion bool
+}
+
+// An eventQueue is an event channel with its buffering options.
+type eventQueue struct {
+	// Dropped is the number of events discarded because the channel was full.
+	// It is accessed atomically, so it is first for 64-bit alignment.
+	dropped uint64
+
+	ch   chan Event
+	opts EventOptions
+}
+
+func newEventQueue(opts EventOptions) *eventQueue {
+	if opts.BufferSize <= 0 {
+		opts.BufferSize = eventChanSize
+	}
+	if opts.BlockTimeout <= 0 {
+		opts.BlockTimeout = defaultBlockTimeout
+	}
+	return &eventQueue{ch: make(chan Event, opts.BufferSize), opts: opts}
+}
+
+// Send sends an event on the channel, following the overflow policy if it is full.
+// It must be called from the main go routine, or, for a Subscription, with the Subscription locked.
+func (q *eventQueue) send(ev Event) {
+	select {
+	case q.ch <- ev:
+		return
+	default:
+	}
+
+	switch q.opts.Overflow {
+	case DropOldest:
+		for {
+			select {
+			case <-q.ch:
+				atomic.AddUint64(&q.dropped, 1)
+			default:
+			}
+			select {
+			case q.ch <- ev:
+				return
+			default:
+			}
+		}
+	case Block:
+		t := time.NewTimer(q.opts.BlockTimeout)
+		defer t.Stop()
+		select {
+		case q.ch <- ev:
+			return
+		case <-t.C:
+		}
+	}
+	atomic.AddUint64(&q.dropped, 1)
+}
+
+func (q *eventQueue) droppedEvents() uint64 {
+	return atomic.LoadUint64(&q.dropped)
+}
+
+// An eventBatch sends a batch of events that arrived together,
+// coalescing bursts of mouse motion for windows that request it.
+type eventBatch struct {
+	motion *MouseMotionEvent
+}
+
+// Add sends an event, or holds it to coalesce with following events.
+// It must be called from the main go routine.
+func (b *eventBatch) add(ev Event) {
+	if m, ok := ev.(*MouseMotionEvent); ok && m.win != nil && m.win.events.opts.CoalesceMotion {
+		if b.motion != nil && b.motion.win == m.win {
+			m.Xrel += b.motion.Xrel
+			m.Yrel += b.motion.Yrel
+		} else {
+			b.flush()
+		}
+		b.motion = m
+		return
+	}
+	b.flush()
+	sendEvent(ev)
+}
+
+// Flush sends any held event.
+// It must be called from the main go routine.
+func (b *eventBatch) flush() {
+	if b.motion != nil {
+		sendEvent(b.motion)
+		b.motion = nil
+	}
+}
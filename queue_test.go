@@ -0,0 +1,180 @@
+// © 2013 the Ui Authors under the MIT license. See AUTHORS for the list of authors.
+
+package ui
+
+import (
+	"testing"
+	"time"
+)
+
+// Drain returns the events buffered in the queue's channel,
+// up to the end of the channel if it is closed.
+func drain(q *eventQueue) []Event {
+	var evs []Event
+	for {
+		select {
+		case ev, ok := <-q.ch:
+			if !ok {
+				return evs
+			}
+			evs = append(evs, ev)
+		default:
+			return evs
+		}
+	}
+}
+
+func motion(win *Window, x, y, xrel, yrel int) *MouseMotionEvent {
+	return &MouseMotionEvent{eventHeader: eventHeader{win: win}, X: x, Y: y, Xrel: xrel, Yrel: yrel}
+}
+
+func checkEvents(t *testing.T, name string, got []Event, want ...Event) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Errorf("%s: got %d events %v, want %d %v", name, len(got), got, len(want), want)
+		return
+	}
+	for i := range got {
+		if got[i] != want[i] {
+			t.Errorf("%s: event %d is %v, want %v", name, i, got[i], want[i])
+		}
+	}
+}
+
+func TestEventQueueDefaults(t *testing.T) {
+	q := newEventQueue(EventOptions{})
+	if cap(q.ch) != eventChanSize {
+		t.Errorf("default buffer size = %d, want %d", cap(q.ch), eventChanSize)
+	}
+	if q.opts.BlockTimeout != defaultBlockTimeout {
+		t.Errorf("default block timeout = %v, want %v", q.opts.BlockTimeout, defaultBlockTimeout)
+	}
+	if q = newEventQueue(EventOptions{BufferSize: 3}); cap(q.ch) != 3 {
+		t.Errorf("buffer size = %d, want 3", cap(q.ch))
+	}
+}
+
+func TestDropNewest(t *testing.T) {
+	q := newEventQueue(EventOptions{BufferSize: 2, Overflow: DropNewest})
+	e1, e2, e3 := &QuitEvent{}, &QuitEvent{}, &QuitEvent{}
+	q.send(e1)
+	q.send(e2)
+	q.send(e3)
+	checkEvents(t, "DropNewest", drain(q), e1, e2)
+	if n := q.droppedEvents(); n != 1 {
+		t.Errorf("DropNewest dropped %d events, want 1", n)
+	}
+}
+
+func TestDropOldest(t *testing.T) {
+	q := newEventQueue(EventOptions{BufferSize: 2, Overflow: DropOldest})
+	e1, e2, e3, e4 := &QuitEvent{}, &QuitEvent{}, &QuitEvent{}, &QuitEvent{}
+	q.send(e1)
+	q.send(e2)
+	q.send(e3)
+	q.send(e4)
+	checkEvents(t, "DropOldest", drain(q), e3, e4)
+	if n := q.droppedEvents(); n != 2 {
+		t.Errorf("DropOldest dropped %d events, want 2", n)
+	}
+}
+
+func TestBlockTimeout(t *testing.T) {
+	timeout := 20 * time.Millisecond
+	q := newEventQueue(EventOptions{BufferSize: 1, Overflow: Block, BlockTimeout: timeout})
+	e1, e2 := &QuitEvent{}, &QuitEvent{}
+	q.send(e1)
+	start := time.Now()
+	q.send(e2)
+	if d := time.Since(start); d < timeout {
+		t.Errorf("Block returned after %v, want at least %v", d, timeout)
+	}
+	checkEvents(t, "Block", drain(q), e1)
+	if n := q.droppedEvents(); n != 1 {
+		t.Errorf("Block dropped %d events, want 1", n)
+	}
+}
+
+func TestBlockReceived(t *testing.T) {
+	q := newEventQueue(EventOptions{BufferSize: 1, Overflow: Block, BlockTimeout: time.Minute})
+	e1, e2 := &QuitEvent{}, &QuitEvent{}
+	q.send(e1)
+	got := make(chan Event)
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		got <- <-q.ch
+	}()
+	q.send(e2)
+	checkEvents(t, "Block", []Event{<-got}, e1)
+	checkEvents(t, "Block", drain(q), e2)
+	if n := q.droppedEvents(); n != 0 {
+		t.Errorf("Block dropped %d events, want 0", n)
+	}
+}
+
+func TestCoalesceMotion(t *testing.T) {
+	a := &Window{events: newEventQueue(EventOptions{CoalesceMotion: true})}
+	b := &Window{events: newEventQueue(EventOptions{CoalesceMotion: true})}
+	key := &KeyboardEvent{eventHeader: eventHeader{win: a}}
+
+	var batch eventBatch
+	batch.add(motion(a, 1, 1, 1, 1))
+	batch.add(motion(a, 3, 4, 2, 3))
+	batch.add(motion(b, 5, 5, 5, 5))
+	batch.add(motion(a, 6, 6, 3, 2))
+	batch.add(key)
+	batch.add(motion(a, 7, 7, 1, 1))
+	batch.add(motion(a, 9, 7, 2, 0))
+	batch.flush()
+
+	evs := drain(a.events)
+	if len(evs) != 4 {
+		t.Fatalf("window a got %d events %v, want 4", len(evs), evs)
+	}
+	want := []MouseMotionEvent{{X: 3, Y: 4, Xrel: 3, Yrel: 4}, {X: 6, Y: 6, Xrel: 3, Yrel: 2}, {}, {X: 9, Y: 7, Xrel: 3, Yrel: 1}}
+	for i, ev := range evs {
+		if i == 2 {
+			if ev != key {
+				t.Errorf("window a event 2 is %v, want %v", ev, key)
+			}
+			continue
+		}
+		m, ok := ev.(*MouseMotionEvent)
+		if !ok {
+			t.Errorf("window a event %d is %v, want a MouseMotionEvent", i, ev)
+			continue
+		}
+		w := want[i]
+		if m.X != w.X || m.Y != w.Y || m.Xrel != w.Xrel || m.Yrel != w.Yrel {
+			t.Errorf("window a event %d is %v, want %v", i, m, &w)
+		}
+	}
+
+	evs = drain(b.events)
+	if len(evs) != 1 {
+		t.Fatalf("window b got %d events %v, want 1", len(evs), evs)
+	}
+	if m := evs[0].(*MouseMotionEvent); m.X != 5 || m.Xrel != 5 {
+		t.Errorf("window b event is %v, want X: 5, Xrel: 5", m)
+	}
+}
+
+func TestNoCoalesceMotion(t *testing.T) {
+	w := &Window{events: newEventQueue(EventOptions{})}
+	m1, m2 := motion(w, 1, 1, 1, 1), motion(w, 2, 2, 1, 1)
+
+	var batch eventBatch
+	batch.add(m1)
+	batch.add(m2)
+	batch.flush()
+	checkEvents(t, "without CoalesceMotion", drain(w.events), m1, m2)
+}
+
+func TestSendDestroyed(t *testing.T) {
+	w := &Window{events: newEventQueue(EventOptions{}), destroyed: true}
+	sendEvent(motion(w, 1, 1, 1, 1))
+	sendEvent(&KeyboardEvent{})
+	if evs := drain(w.events); len(evs) != 0 {
+		t.Errorf("destroyed window got events %v", evs)
+	}
+}
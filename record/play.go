@@ -0,0 +1,82 @@
+// © 2013 the Ui Authors under the MIT license. See AUTHORS for the list of authors.
+
+package record
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/velour/ui"
+)
+
+// A Player replays recordings into windows.
+//
+// Replayed events go through SDL's event queue, so they are delivered with
+// new timestamps, and motion may be coalesced if the window coalesces motion.
+type Player struct {
+	// Window is the window into which events are injected,
+	// unless their window is in Windows.
+	Window *ui.Window
+
+	// Windows maps the IDs of recorded windows to the windows into which
+	// their events are injected.
+	Windows map[uint32]*ui.Window
+
+	// Speed is the rate of playback: 1 plays with the original timing,
+	// and 2 plays twice as fast.  Zero is treated as 1.
+	// Use math.Inf(1) to play without delay.
+	Speed float64
+}
+
+// Play replays the recording read from r.
+// It returns when the recording ends, or with the context's error if it is done first.
+// It returns an error if an event has no window to be injected into.
+func (p *Player) Play(ctx context.Context, r io.Reader) error {
+	rd, err := NewReader(r)
+	if err != nil {
+		return err
+	}
+	speed := p.Speed
+	if speed == 0 {
+		speed = 1
+	}
+	start := time.Now()
+	first := true
+	var base time.Duration
+	for {
+		e, err := rd.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if first {
+			base, first = e.Time, false
+		}
+		due := start.Add(time.Duration(float64(e.Time-base) / speed))
+		if d := time.Until(due); d > 0 {
+			t := time.NewTimer(d)
+			select {
+			case <-t.C:
+			case <-ctx.Done():
+				t.Stop()
+				return ctx.Err()
+			}
+		} else if err := ctx.Err(); err != nil {
+			return err
+		}
+		win := p.Window
+		if w, ok := p.Windows[e.Window]; ok {
+			win = w
+		}
+		if win == nil {
+			return fmt.Errorf("record: no window for recorded window %d", e.Window)
+		}
+		if err := win.Inject(e.Event); err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,204 @@
+// © 2013 the Ui Authors under the MIT license. See AUTHORS for the list of authors.
+
+// Package record records the events of a user interface session and replays them.
+//
+// A recording is a stream of JSON values, one per line.  The first line is
+// a header giving the format version:
+//
+//	{"version":1}
+//
+// Each following line is an event, with its timestamp in nanoseconds, the ID
+// of its window (zero for application events), the name of its type in
+// package ui, and its exported fields:
+//
+//	{"time":1520000000,"window":1,"type":"KeyboardEvent","event":{"Down":true,"Repeat":false,"Key":27,"Scancode":41,"Mod":0}}
+//
+// Enumerated fields, such as keys and buttons, are recorded as SDL's numeric values.
+//
+// To record the events of a window, tee its event channel through a Recorder:
+//
+//	rec, err := record.NewRecorder(f)
+//	...
+//	for ev := range rec.Tee(win.Events()) {
+//		handle(ev)
+//	}
+//
+// A Player replays a recording into a window with Window.Inject.
+package record
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/velour/ui"
+)
+
+// Version is the version of the recording format written by Recorder.
+const Version = 1
+
+// ErrVersion is returned when reading a recording of an unsupported format version.
+var ErrVersion = errors.New("record: unsupported recording version")
+
+// EventTypes maps the names of the recordable event types to constructors.
+var eventTypes = map[string]func() ui.Event{
+	"WindowEvent":           func() ui.Event { return new(ui.WindowEvent) },
+	"KeyboardEvent":         func() ui.Event { return new(ui.KeyboardEvent) },
+	"TextInputEvent":        func() ui.Event { return new(ui.TextInputEvent) },
+	"TextEditingEvent":      func() ui.Event { return new(ui.TextEditingEvent) },
+	"MouseMotionEvent":      func() ui.Event { return new(ui.MouseMotionEvent) },
+	"MouseButtonEvent":      func() ui.Event { return new(ui.MouseButtonEvent) },
+	"MouseWheelEvent":       func() ui.Event { return new(ui.MouseWheelEvent) },
+	"TouchEvent":            func() ui.Event { return new(ui.TouchEvent) },
+	"MultiGestureEvent":     func() ui.Event { return new(ui.MultiGestureEvent) },
+	"DropEvent":             func() ui.Event { return new(ui.DropEvent) },
+	"ControllerDeviceEvent": func() ui.Event { return new(ui.ControllerDeviceEvent) },
+	"ControllerButtonEvent": func() ui.Event { return new(ui.ControllerButtonEvent) },
+	"ControllerAxisEvent":   func() ui.Event { return new(ui.ControllerAxisEvent) },
+	"QuitEvent":             func() ui.Event { return new(ui.QuitEvent) },
+	"ClipboardUpdateEvent":  func() ui.Event { return new(ui.ClipboardUpdateEvent) },
+}
+
+type header struct {
+	Version int `json:"version"`
+}
+
+type line struct {
+	Time   time.Duration   `json:"time"`
+	Window uint32          `json:"window"`
+	Type   string          `json:"type"`
+	Event  json.RawMessage `json:"event"`
+}
+
+// An Entry is a recorded event.
+type Entry struct {
+	// Time is the event's timestamp, as returned by its Timestamp method.
+	Time time.Duration
+
+	// Window is the ID of the event's window, or zero if it had none.
+	Window uint32
+
+	// Event is the event.  Its Window and Timestamp methods return
+	// the zero values; use the Window and Time fields of the Entry instead.
+	Event ui.Event
+}
+
+// A Recorder writes events to a recording.
+// It is safe to use a Recorder from multiple go routines.
+type Recorder struct {
+	mu      sync.Mutex
+	enc     *json.Encoder
+	err     error
+	skipped int
+}
+
+// NewRecorder returns a Recorder that writes a recording to w.
+// The header is written immediately.
+func NewRecorder(w io.Writer) (*Recorder, error) {
+	enc := json.NewEncoder(w)
+	if err := enc.Encode(header{Version: Version}); err != nil {
+		return nil, err
+	}
+	return &Recorder{enc: enc}, nil
+}
+
+// Record writes an event to the recording.
+// After an error, Record does nothing, and returns the error.
+//
+// Events of types that cannot be recorded, such as UserEvent, are skipped:
+// Record returns an error for them, and counts them, but the recording continues.
+func (r *Recorder) Record(ev ui.Event) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.err != nil {
+		return r.err
+	}
+	name := reflect.TypeOf(ev).Elem().Name()
+	if _, ok := eventTypes[name]; !ok {
+		r.skipped++
+		return fmt.Errorf("record: cannot record %T", ev)
+	}
+	data, err := json.Marshal(ev)
+	if err != nil {
+		r.err = err
+		return err
+	}
+	l := line{Time: ev.Timestamp(), Type: name, Event: data}
+	if win := ev.Window(); win != nil {
+		l.Window = win.ID()
+	}
+	if err := r.enc.Encode(l); err != nil {
+		r.err = err
+	}
+	return r.err
+}
+
+// Tee returns a channel that receives each event from in, after it is recorded.
+// The returned channel is closed when in is closed.
+// Events that cannot be recorded are still forwarded; see Err and Skipped.
+func (r *Recorder) Tee(in <-chan ui.Event) <-chan ui.Event {
+	out := make(chan ui.Event, cap(in))
+	go func() {
+		defer close(out)
+		for ev := range in {
+			r.Record(ev)
+			out <- ev
+		}
+	}()
+	return out
+}
+
+// Err returns the first error that occurred writing the recording, if any.
+func (r *Recorder) Err() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.err
+}
+
+// Skipped returns the number of events that were not recorded because
+// their type cannot be recorded.
+func (r *Recorder) Skipped() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.skipped
+}
+
+// A Reader reads the entries of a recording.
+type Reader struct {
+	dec *json.Decoder
+}
+
+// NewReader returns a Reader for the recording read from r.
+// It returns ErrVersion if the recording's format version is not supported.
+func NewReader(r io.Reader) (*Reader, error) {
+	dec := json.NewDecoder(r)
+	var h header
+	if err := dec.Decode(&h); err != nil {
+		return nil, err
+	}
+	if h.Version != Version {
+		return nil, ErrVersion
+	}
+	return &Reader{dec: dec}, nil
+}
+
+// Next returns the next entry of the recording, or io.EOF at the end of the recording.
+func (r *Reader) Next() (Entry, error) {
+	var l line
+	if err := r.dec.Decode(&l); err != nil {
+		return Entry{}, err
+	}
+	mk, ok := eventTypes[l.Type]
+	if !ok {
+		return Entry{}, fmt.Errorf("record: unknown event type %q", l.Type)
+	}
+	ev := mk()
+	if err := json.Unmarshal(l.Event, ev); err != nil {
+		return Entry{}, err
+	}
+	return Entry{Time: l.Time, Window: l.Window, Event: ev}, nil
+}
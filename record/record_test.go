@@ -0,0 +1,223 @@
+// © 2013 the Ui Authors under the MIT license. See AUTHORS for the list of authors.
+
+package record
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/velour/ui"
+	"github.com/velour/ui/uitest"
+)
+
+// Playing recordings injects events into windows, so the tests need the user interface running.
+func TestMain(m *testing.M) {
+	uitest.Main(m)
+}
+
+// TestEvents has an event of each recordable type.
+var testEvents = []ui.Event{
+	&ui.WindowEvent{Event: ui.WindowResized, Data1: 640, Data2: 480},
+	&ui.KeyboardEvent{Down: true, Repeat: true, Key: ui.KeyA, Scancode: ui.ScancodeA, Mod: ui.ModLShift},
+	&ui.TextInputEvent{Text: "héllo"},
+	&ui.TextEditingEvent{Text: "ni", Start: 1, Length: 2},
+	&ui.MouseMotionEvent{X: 10, Y: 20, Xrel: -1, Yrel: 2},
+	&ui.MouseButtonEvent{Button: ui.ButtonRight, Down: true, Clicks: 2, X: 3, Y: 4},
+	&ui.MouseWheelEvent{X: 1, Y: -2, PreciseX: 0.5, PreciseY: -1.75, Flipped: true},
+	&ui.TouchEvent{Event: ui.TouchMotion, TouchID: 7, FingerID: 3, X: 0.25, Y: 0.5, DX: 0.125, DY: -0.0625, WinX: 8, WinY: 9, Pressure: 0.75},
+	&ui.MultiGestureEvent{TouchID: 7, DTheta: 0.5, DDist: -0.25, X: 0.5, Y: 0.5, WinX: 1, WinY: 2, NumFingers: 2},
+	&ui.DropEvent{Files: []string{"/tmp/a", "/tmp/b"}, Texts: []string{"text"}},
+	&ui.ControllerDeviceEvent{Event: ui.ControllerAdded, Index: 1, ID: 2},
+	&ui.ControllerButtonEvent{ID: 2, Button: ui.ControllerButtonB, Down: true},
+	&ui.ControllerAxisEvent{ID: 2, Axis: ui.ControllerAxisLeftX, Value: -32768},
+	&ui.QuitEvent{},
+	&ui.ClipboardUpdateEvent{},
+}
+
+func TestRoundTrip(t *testing.T) {
+	seen := make(map[string]bool)
+	for _, ev := range testEvents {
+		seen[reflect.TypeOf(ev).Elem().Name()] = true
+	}
+	for name := range eventTypes {
+		if !seen[name] {
+			t.Errorf("no test event of type %s", name)
+		}
+	}
+
+	var buf bytes.Buffer
+	rec, err := NewRecorder(&buf)
+	if err != nil {
+		t.Fatalf("NewRecorder: %v", err)
+	}
+	for _, ev := range testEvents {
+		if err := rec.Record(ev); err != nil {
+			t.Fatalf("Record(%v): %v", ev, err)
+		}
+	}
+	if err := rec.Err(); err != nil {
+		t.Fatalf("Err() = %v", err)
+	}
+
+	rd, err := NewReader(&buf)
+	if err != nil {
+		t.Fatalf("NewReader: %v", err)
+	}
+	for _, want := range testEvents {
+		e, err := rd.Next()
+		if err != nil {
+			t.Fatalf("Next: %v, want %v", err, want)
+		}
+		if e.Time != 0 || e.Window != 0 || !reflect.DeepEqual(e.Event, want) {
+			t.Errorf("Next() = %v, %d, %v, want 0, 0, %v", e.Time, e.Window, e.Event, want)
+		}
+	}
+	if e, err := rd.Next(); err != io.EOF {
+		t.Errorf("Next() at the end = %v, %v, want io.EOF", e.Event, err)
+	}
+}
+
+func TestVersion(t *testing.T) {
+	if _, err := NewReader(strings.NewReader(`{"version":2}` + "\n")); err != ErrVersion {
+		t.Errorf("NewReader of version 2 = %v, want ErrVersion", err)
+	}
+	if _, err := NewReader(strings.NewReader("")); err == nil {
+		t.Errorf("NewReader of an empty recording succeeded")
+	}
+}
+
+func TestUnknownType(t *testing.T) {
+	rd, err := NewReader(strings.NewReader(`{"version":1}
+{"time":0,"window":0,"type":"SensorEvent","event":{}}
+`))
+	if err != nil {
+		t.Fatalf("NewReader: %v", err)
+	}
+	if e, err := rd.Next(); err == nil {
+		t.Errorf("Next() of an unknown type = %v, want an error", e.Event)
+	}
+}
+
+func TestPlayNoWindow(t *testing.T) {
+	r := strings.NewReader(`{"version":1}
+{"time":0,"window":3,"type":"QuitEvent","event":{}}
+`)
+	err := (&Player{}).Play(context.Background(), r)
+	if err == nil || !strings.Contains(err.Error(), "recorded window 3") {
+		t.Errorf("Play without a window = %v, want a no window error", err)
+	}
+}
+
+func TestSkipped(t *testing.T) {
+	var buf bytes.Buffer
+	rec, err := NewRecorder(&buf)
+	if err != nil {
+		t.Fatalf("NewRecorder: %v", err)
+	}
+	if err := rec.Record(&ui.UserEvent{Value: 1}); err == nil {
+		t.Errorf("Record(UserEvent) succeeded")
+	}
+	if err := rec.Record(&ui.QuitEvent{}); err != nil {
+		t.Errorf("Record after a skipped event: %v", err)
+	}
+	if n := rec.Skipped(); n != 1 {
+		t.Errorf("Skipped() = %d, want 1", n)
+	}
+	if err := rec.Err(); err != nil {
+		t.Errorf("Err() = %v, want nil", err)
+	}
+
+	in := make(chan ui.Event, 2)
+	in <- &ui.UserEvent{Value: 2}
+	in <- &ui.QuitEvent{}
+	close(in)
+	n := 0
+	for range rec.Tee(in) {
+		n++
+	}
+	if n != 2 {
+		t.Errorf("Tee forwarded %d events, want 2", n)
+	}
+	if n := rec.Skipped(); n != 2 {
+		t.Errorf("Skipped() after Tee = %d, want 2", n)
+	}
+}
+
+// NextInput returns the next event delivered to the window that is not a WindowEvent,
+// such as those sent by SDL when the window is shown, or nil if none arrives within a second.
+func nextInput(win *ui.Window) ui.Event {
+	for {
+		ev := uitest.Next(win, time.Second)
+		if _, ok := ev.(*ui.WindowEvent); !ok {
+			return ev
+		}
+	}
+}
+
+func TestPlay(t *testing.T) {
+	from, err := ui.NewWindowErr("from", 32, 32)
+	if err != nil {
+		t.Fatalf("NewWindowErr: %v", err)
+	}
+	defer from.Destroy()
+	to, err := ui.NewWindowErr("to", 32, 32)
+	if err != nil {
+		t.Fatalf("NewWindowErr: %v", err)
+	}
+	defer to.Destroy()
+
+	// Record events as delivered to a window, with their window and timestamps.
+	var buf bytes.Buffer
+	rec, err := NewRecorder(&buf)
+	if err != nil {
+		t.Fatalf("NewRecorder: %v", err)
+	}
+	var want []ui.Event
+	for _, ev := range []ui.Event{
+		&ui.KeyboardEvent{Down: true, Key: ui.KeyA, Scancode: ui.ScancodeA, Mod: ui.ModLShift},
+		&ui.TextInputEvent{Text: "A"},
+		&ui.MouseButtonEvent{Button: ui.ButtonLeft, Down: true, Clicks: 2, X: 3, Y: 4},
+		&ui.MouseWheelEvent{X: 1, Y: -1, PreciseX: 1, PreciseY: -1},
+	} {
+		if err := from.Inject(ev); err != nil {
+			t.Fatalf("Inject(%v): %v", ev, err)
+		}
+		got := nextInput(from)
+		if got == nil {
+			t.Fatalf("Inject(%v): no event delivered", ev)
+		}
+		if err := rec.Record(got); err != nil {
+			t.Fatalf("Record(%v): %v", got, err)
+		}
+		want = append(want, got)
+	}
+
+	p := &Player{Windows: map[uint32]*ui.Window{from.ID(): to}, Speed: math.Inf(1)}
+	if err := p.Play(context.Background(), &buf); err != nil {
+		t.Fatalf("Play: %v", err)
+	}
+	for _, w := range want {
+		got := nextInput(to)
+		if got == nil {
+			t.Fatalf("no event played for %v", w)
+		}
+		// The played event is a copy of the recorded one, delivered to the other window.
+		if reflect.TypeOf(got) != reflect.TypeOf(w) || got.String() != w.String() {
+			t.Errorf("played %v, want %v", got, w)
+		}
+		if got.Window() != to {
+			t.Errorf("%v played into %v, want the window mapped from %d", got, got.Window(), from.ID())
+		}
+	}
+	if ev := uitest.Next(from, 50*time.Millisecond); ev != nil {
+		if _, ok := ev.(*ui.WindowEvent); !ok {
+			t.Errorf("recorded window received %v during playback", ev)
+		}
+	}
+}
@@ -0,0 +1,162 @@
+// © 2013 the Ui Authors under the MIT license. See AUTHORS for the list of authors.
+
+package ui
+
+/*
+#include "ui.h"
+*/
+import "C"
+
+import (
+	"errors"
+)
+
+// RendererOptions select the renderer used to draw to a window.
+//
+// The zero value selects the first hardware accelerated renderer,
+// falling back to a software renderer if none is available.
+type RendererOptions struct {
+	// Driver is the name of the SDL render driver to use, such as "opengl",
+	// "direct3d", "metal" or "software".  If Driver is empty, the first driver
+	// supporting the other options is used.
+	Driver string
+
+	// Software selects a software renderer instead of a hardware accelerated one.
+	// Software is implied when the user interface is headless.
+	Software bool
+
+	// VSync synchronizes presenting the window with the display's refresh rate.
+	VSync bool
+
+	// NoFallback disables falling back to a software renderer
+	// when the requested renderer cannot be created.
+	NoFallback bool
+}
+
+// NewRenderer returns a new renderer for the window, selected by opts.
+// It must be called from the main go routine.
+func newRenderer(win *C.SDL_Window, opts RendererOptions) (*C.SDL_Renderer, error) {
+	index := C.int(-1)
+	if opts.Driver != "" {
+		index = renderDriverIndex(opts.Driver)
+		if index < 0 && opts.NoFallback {
+			return nil, errors.New("ui: unknown render driver " + opts.Driver)
+		}
+	}
+	flags := C.Uint32(C.SDL_RENDERER_ACCELERATED)
+	if opts.Software || headless {
+		flags = C.SDL_RENDERER_SOFTWARE
+	}
+	if opts.VSync {
+		flags |= C.SDL_RENDERER_PRESENTVSYNC
+	}
+
+	if rend := C.SDL_CreateRenderer(win, index, flags); rend != nil {
+		return rend, nil
+	}
+	err := sdlError("SDL_CreateRenderer")
+	if opts.NoFallback || (index < 0 && flags&C.SDL_RENDERER_SOFTWARE != 0) {
+		return nil, err
+	}
+
+	flags &^= C.SDL_RENDERER_ACCELERATED
+	flags |= C.SDL_RENDERER_SOFTWARE
+	if rend := C.SDL_CreateRenderer(win, -1, flags); rend != nil {
+		return rend, nil
+	}
+	return nil, err
+}
+
+// RenderDriverIndex returns the index of the named render driver, or -1 if there is none.
+func renderDriverIndex(name string) C.int {
+	n := C.SDL_GetNumRenderDrivers()
+	for i := C.int(0); i < n; i++ {
+		var info C.SDL_RendererInfo
+		if C.SDL_GetRenderDriverInfo(i, &info) == 0 && C.GoString(info.name) == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// A PixelFormat is an SDL pixel format.
+type PixelFormat C.Uint32
+
+func (f PixelFormat) String() string {
+	return C.GoString(C.SDL_GetPixelFormatName(C.Uint32(f)))
+}
+
+// RendererInfo describes a renderer.
+type RendererInfo struct {
+	// Name is the name of the render driver.
+	Name string
+
+	// Software is whether the renderer is a software renderer.
+	Software bool
+
+	// Accelerated is whether the renderer uses hardware acceleration.
+	Accelerated bool
+
+	// VSync is whether presenting is synchronized with the display's refresh rate.
+	VSync bool
+
+	// MaxTextureWidth and MaxTextureHeight are the maximum size of a texture, in pixels.
+	// Images larger than this cannot be drawn.  They are zero if there is no limit.
+	MaxTextureWidth, MaxTextureHeight int
+
+	// PixelFormats are the texture pixel formats supported by the renderer.
+	PixelFormats []PixelFormat
+}
+
+func newRendererInfo(info *C.SDL_RendererInfo) RendererInfo {
+	ri := RendererInfo{
+		Name:             C.GoString(info.name),
+		Software:         info.flags&C.SDL_RENDERER_SOFTWARE != 0,
+		Accelerated:      info.flags&C.SDL_RENDERER_ACCELERATED != 0,
+		VSync:            info.flags&C.SDL_RENDERER_PRESENTVSYNC != 0,
+		MaxTextureWidth:  int(info.max_texture_width),
+		MaxTextureHeight: int(info.max_texture_height),
+	}
+	for i := 0; i < int(info.num_texture_formats); i++ {
+		ri.PixelFormats = append(ri.PixelFormats, PixelFormat(info.texture_formats[i]))
+	}
+	return ri
+}
+
+// RenderDrivers returns information about the render drivers available on this system.
+// The names in the returned RendererInfos can be used for RendererOptions.Driver.
+func RenderDrivers() ([]RendererInfo, error) {
+	var infos []RendererInfo
+	err := doErr(func() error {
+		n := C.SDL_GetNumRenderDrivers()
+		if n < 0 {
+			return sdlError("SDL_GetNumRenderDrivers")
+		}
+		for i := C.int(0); i < n; i++ {
+			var info C.SDL_RendererInfo
+			if C.SDL_GetRenderDriverInfo(i, &info) < 0 {
+				return sdlError("SDL_GetRenderDriverInfo")
+			}
+			infos = append(infos, newRendererInfo(&info))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return infos, nil
+}
+
+// RendererInfo returns information about the window's renderer.
+func (win *Window) RendererInfo() (RendererInfo, error) {
+	var ri RendererInfo
+	err := win.do(func() error {
+		var info C.SDL_RendererInfo
+		if C.SDL_GetRendererInfo(win.rend, &info) < 0 {
+			return sdlError("SDL_GetRendererInfo")
+		}
+		ri = newRendererInfo(&info)
+		return nil
+	})
+	return ri, err
+}
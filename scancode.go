@@ -0,0 +1,456 @@
+// © 2013 the Ui Authors under the MIT license. See AUTHORS for the list of authors.
+
+package ui
+
+/*
+#include "ui.h"
+*/
+import "C"
+
+import (
+	"strconv"
+)
+
+// A Scancode identifies a physical key by its position on the keyboard,
+// independent of the keyboard layout.  Scancodes are named after the key
+// at that position on a US keyboard layout.  They are useful for bindings
+// that depend on position, such as the W, A, S and D keys for movement.
+type Scancode C.SDL_Scancode
+
+const (
+	// ScancodeUnknown is an unknown key.
+	ScancodeUnknown Scancode = C.SDL_SCANCODE_UNKNOWN
+
+	// ScancodeA is the A key.
+	ScancodeA Scancode = C.SDL_SCANCODE_A
+
+	// ScancodeB is the B key.
+	ScancodeB Scancode = C.SDL_SCANCODE_B
+
+	// ScancodeC is the C key.
+	ScancodeC Scancode = C.SDL_SCANCODE_C
+
+	// ScancodeD is the D key.
+	ScancodeD Scancode = C.SDL_SCANCODE_D
+
+	// ScancodeE is the E key.
+	ScancodeE Scancode = C.SDL_SCANCODE_E
+
+	// ScancodeF is the F key.
+	ScancodeF Scancode = C.SDL_SCANCODE_F
+
+	// ScancodeG is the G key.
+	ScancodeG Scancode = C.SDL_SCANCODE_G
+
+	// ScancodeH is the H key.
+	ScancodeH Scancode = C.SDL_SCANCODE_H
+
+	// ScancodeI is the I key.
+	ScancodeI Scancode = C.SDL_SCANCODE_I
+
+	// ScancodeJ is the J key.
+	ScancodeJ Scancode = C.SDL_SCANCODE_J
+
+	// ScancodeK is the K key.
+	ScancodeK Scancode = C.SDL_SCANCODE_K
+
+	// ScancodeL is the L key.
+	ScancodeL Scancode = C.SDL_SCANCODE_L
+
+	// ScancodeM is the M key.
+	ScancodeM Scancode = C.SDL_SCANCODE_M
+
+	// ScancodeN is the N key.
+	ScancodeN Scancode = C.SDL_SCANCODE_N
+
+	// ScancodeO is the O key.
+	ScancodeO Scancode = C.SDL_SCANCODE_O
+
+	// ScancodeP is the P key.
+	ScancodeP Scancode = C.SDL_SCANCODE_P
+
+	// ScancodeQ is the Q key.
+	ScancodeQ Scancode = C.SDL_SCANCODE_Q
+
+	// ScancodeR is the R key.
+	ScancodeR Scancode = C.SDL_SCANCODE_R
+
+	// ScancodeS is the S key.
+	ScancodeS Scancode = C.SDL_SCANCODE_S
+
+	// ScancodeT is the T key.
+	ScancodeT Scancode = C.SDL_SCANCODE_T
+
+	// ScancodeU is the U key.
+	ScancodeU Scancode = C.SDL_SCANCODE_U
+
+	// ScancodeV is the V key.
+	ScancodeV Scancode = C.SDL_SCANCODE_V
+
+	// ScancodeW is the W key.
+	ScancodeW Scancode = C.SDL_SCANCODE_W
+
+	// ScancodeX is the X key.
+	ScancodeX Scancode = C.SDL_SCANCODE_X
+
+	// ScancodeY is the Y key.
+	ScancodeY Scancode = C.SDL_SCANCODE_Y
+
+	// ScancodeZ is the Z key.
+	ScancodeZ Scancode = C.SDL_SCANCODE_Z
+
+	// Scancode1 is the 1 key on the main keyboard.
+	Scancode1 Scancode = C.SDL_SCANCODE_1
+
+	// Scancode2 is the 2 key on the main keyboard.
+	Scancode2 Scancode = C.SDL_SCANCODE_2
+
+	// Scancode3 is the 3 key on the main keyboard.
+	Scancode3 Scancode = C.SDL_SCANCODE_3
+
+	// Scancode4 is the 4 key on the main keyboard.
+	Scancode4 Scancode = C.SDL_SCANCODE_4
+
+	// Scancode5 is the 5 key on the main keyboard.
+	Scancode5 Scancode = C.SDL_SCANCODE_5
+
+	// Scancode6 is the 6 key on the main keyboard.
+	Scancode6 Scancode = C.SDL_SCANCODE_6
+
+	// Scancode7 is the 7 key on the main keyboard.
+	Scancode7 Scancode = C.SDL_SCANCODE_7
+
+	// Scancode8 is the 8 key on the main keyboard.
+	Scancode8 Scancode = C.SDL_SCANCODE_8
+
+	// Scancode9 is the 9 key on the main keyboard.
+	Scancode9 Scancode = C.SDL_SCANCODE_9
+
+	// Scancode0 is the 0 key on the main keyboard.
+	Scancode0 Scancode = C.SDL_SCANCODE_0
+
+	// ScancodeReturn is the Return (Enter) key on the main keyboard.
+	ScancodeReturn Scancode = C.SDL_SCANCODE_RETURN
+
+	// ScancodeEscape is the Escape key.
+	ScancodeEscape Scancode = C.SDL_SCANCODE_ESCAPE
+
+	// ScancodeBackspace is the Backspace key.
+	ScancodeBackspace Scancode = C.SDL_SCANCODE_BACKSPACE
+
+	// ScancodeTab is the Tab key.
+	ScancodeTab Scancode = C.SDL_SCANCODE_TAB
+
+	// ScancodeSpace is the Space bar.
+	ScancodeSpace Scancode = C.SDL_SCANCODE_SPACE
+
+	// ScancodeMinus is the key right of 0 on a US layout.
+	ScancodeMinus Scancode = C.SDL_SCANCODE_MINUS
+
+	// ScancodeEquals is the key left of Backspace on a US layout.
+	ScancodeEquals Scancode = C.SDL_SCANCODE_EQUALS
+
+	// ScancodeLeftBracket is the key right of P on a US layout.
+	ScancodeLeftBracket Scancode = C.SDL_SCANCODE_LEFTBRACKET
+
+	// ScancodeRightBracket is the second key right of P on a US layout.
+	ScancodeRightBracket Scancode = C.SDL_SCANCODE_RIGHTBRACKET
+
+	// ScancodeBackslash is the key above Return on a US layout.
+	ScancodeBackslash Scancode = C.SDL_SCANCODE_BACKSLASH
+
+	// ScancodeSemicolon is the key right of L on a US layout.
+	ScancodeSemicolon Scancode = C.SDL_SCANCODE_SEMICOLON
+
+	// ScancodeApostrophe is the second key right of L on a US layout.
+	ScancodeApostrophe Scancode = C.SDL_SCANCODE_APOSTROPHE
+
+	// ScancodeGrave is the key left of 1 on a US layout.
+	ScancodeGrave Scancode = C.SDL_SCANCODE_GRAVE
+
+	// ScancodeComma is the key right of M on a US layout.
+	ScancodeComma Scancode = C.SDL_SCANCODE_COMMA
+
+	// ScancodePeriod is the second key right of M on a US layout.
+	ScancodePeriod Scancode = C.SDL_SCANCODE_PERIOD
+
+	// ScancodeSlash is the third key right of M on a US layout.
+	ScancodeSlash Scancode = C.SDL_SCANCODE_SLASH
+
+	// ScancodeCapsLock is the Caps Lock key.
+	ScancodeCapsLock Scancode = C.SDL_SCANCODE_CAPSLOCK
+
+	// ScancodeF1 is the F1 key.
+	ScancodeF1 Scancode = C.SDL_SCANCODE_F1
+
+	// ScancodeF2 is the F2 key.
+	ScancodeF2 Scancode = C.SDL_SCANCODE_F2
+
+	// ScancodeF3 is the F3 key.
+	ScancodeF3 Scancode = C.SDL_SCANCODE_F3
+
+	// ScancodeF4 is the F4 key.
+	ScancodeF4 Scancode = C.SDL_SCANCODE_F4
+
+	// ScancodeF5 is the F5 key.
+	ScancodeF5 Scancode = C.SDL_SCANCODE_F5
+
+	// ScancodeF6 is the F6 key.
+	ScancodeF6 Scancode = C.SDL_SCANCODE_F6
+
+	// ScancodeF7 is the F7 key.
+	ScancodeF7 Scancode = C.SDL_SCANCODE_F7
+
+	// ScancodeF8 is the F8 key.
+	ScancodeF8 Scancode = C.SDL_SCANCODE_F8
+
+	// ScancodeF9 is the F9 key.
+	ScancodeF9 Scancode = C.SDL_SCANCODE_F9
+
+	// ScancodeF10 is the F10 key.
+	ScancodeF10 Scancode = C.SDL_SCANCODE_F10
+
+	// ScancodeF11 is the F11 key.
+	ScancodeF11 Scancode = C.SDL_SCANCODE_F11
+
+	// ScancodeF12 is the F12 key.
+	ScancodeF12 Scancode = C.SDL_SCANCODE_F12
+
+	// ScancodePrintScreen is the Print Screen key.
+	ScancodePrintScreen Scancode = C.SDL_SCANCODE_PRINTSCREEN
+
+	// ScancodeScrollLock is the Scroll Lock key.
+	ScancodeScrollLock Scancode = C.SDL_SCANCODE_SCROLLLOCK
+
+	// ScancodePause is the Pause (Break) key.
+	ScancodePause Scancode = C.SDL_SCANCODE_PAUSE
+
+	// ScancodeInsert is the Insert key.
+	ScancodeInsert Scancode = C.SDL_SCANCODE_INSERT
+
+	// ScancodeHome is the Home key.
+	ScancodeHome Scancode = C.SDL_SCANCODE_HOME
+
+	// ScancodePageUp is the Page Up key.
+	ScancodePageUp Scancode = C.SDL_SCANCODE_PAGEUP
+
+	// ScancodeDelete is the Delete key.
+	ScancodeDelete Scancode = C.SDL_SCANCODE_DELETE
+
+	// ScancodeEnd is the End key.
+	ScancodeEnd Scancode = C.SDL_SCANCODE_END
+
+	// ScancodePageDown is the Page Down key.
+	ScancodePageDown Scancode = C.SDL_SCANCODE_PAGEDOWN
+
+	// ScancodeRight is the Right arrow key.
+	ScancodeRight Scancode = C.SDL_SCANCODE_RIGHT
+
+	// ScancodeLeft is the Left arrow key.
+	ScancodeLeft Scancode = C.SDL_SCANCODE_LEFT
+
+	// ScancodeDown is the Down arrow key.
+	ScancodeDown Scancode = C.SDL_SCANCODE_DOWN
+
+	// ScancodeUp is the Up arrow key.
+	ScancodeUp Scancode = C.SDL_SCANCODE_UP
+
+	// ScancodeNumLockClear is the Num Lock key (PC) / the Clear key (Mac).
+	ScancodeNumLockClear Scancode = C.SDL_SCANCODE_NUMLOCKCLEAR
+
+	// ScancodeKPDivide is the / key on the keypad.
+	ScancodeKPDivide Scancode = C.SDL_SCANCODE_KP_DIVIDE
+
+	// ScancodeKPMultiply is the * key on the keypad.
+	ScancodeKPMultiply Scancode = C.SDL_SCANCODE_KP_MULTIPLY
+
+	// ScancodeKPMinus is the - key on the keypad.
+	ScancodeKPMinus Scancode = C.SDL_SCANCODE_KP_MINUS
+
+	// ScancodeKPPlus is the + key on the keypad.
+	ScancodeKPPlus Scancode = C.SDL_SCANCODE_KP_PLUS
+
+	// ScancodeKPEnter is the Enter key on the keypad.
+	ScancodeKPEnter Scancode = C.SDL_SCANCODE_KP_ENTER
+
+	// ScancodeKP1 is the 1 key on the keypad.
+	ScancodeKP1 Scancode = C.SDL_SCANCODE_KP_1
+
+	// ScancodeKP2 is the 2 key on the keypad.
+	ScancodeKP2 Scancode = C.SDL_SCANCODE_KP_2
+
+	// ScancodeKP3 is the 3 key on the keypad.
+	ScancodeKP3 Scancode = C.SDL_SCANCODE_KP_3
+
+	// ScancodeKP4 is the 4 key on the keypad.
+	ScancodeKP4 Scancode = C.SDL_SCANCODE_KP_4
+
+	// ScancodeKP5 is the 5 key on the keypad.
+	ScancodeKP5 Scancode = C.SDL_SCANCODE_KP_5
+
+	// ScancodeKP6 is the 6 key on the keypad.
+	ScancodeKP6 Scancode = C.SDL_SCANCODE_KP_6
+
+	// ScancodeKP7 is the 7 key on the keypad.
+	ScancodeKP7 Scancode = C.SDL_SCANCODE_KP_7
+
+	// ScancodeKP8 is the 8 key on the keypad.
+	ScancodeKP8 Scancode = C.SDL_SCANCODE_KP_8
+
+	// ScancodeKP9 is the 9 key on the keypad.
+	ScancodeKP9 Scancode = C.SDL_SCANCODE_KP_9
+
+	// ScancodeKP0 is the 0 key on the keypad.
+	ScancodeKP0 Scancode = C.SDL_SCANCODE_KP_0
+
+	// ScancodeKPPeriod is the . key on the keypad.
+	ScancodeKPPeriod Scancode = C.SDL_SCANCODE_KP_PERIOD
+
+	// ScancodeLCtrl is the left Ctrl key.
+	ScancodeLCtrl Scancode = C.SDL_SCANCODE_LCTRL
+
+	// ScancodeLShift is the left Shift key.
+	ScancodeLShift Scancode = C.SDL_SCANCODE_LSHIFT
+
+	// ScancodeLAlt is the left Alt (Option) key.
+	ScancodeLAlt Scancode = C.SDL_SCANCODE_LALT
+
+	// ScancodeLGUI is the left GUI (Windows, Command) key.
+	ScancodeLGUI Scancode = C.SDL_SCANCODE_LGUI
+
+	// ScancodeRCtrl is the right Ctrl key.
+	ScancodeRCtrl Scancode = C.SDL_SCANCODE_RCTRL
+
+	// ScancodeRShift is the right Shift key.
+	ScancodeRShift Scancode = C.SDL_SCANCODE_RSHIFT
+
+	// ScancodeRAlt is the right Alt (AltGr, Option) key.
+	ScancodeRAlt Scancode = C.SDL_SCANCODE_RALT
+
+	// ScancodeRGUI is the right GUI (Windows, Command) key.
+	ScancodeRGUI Scancode = C.SDL_SCANCODE_RGUI
+)
+
+var scancodeNames = map[Scancode]string{
+	ScancodeUnknown:      "ScancodeUnknown",
+	ScancodeA:            "ScancodeA",
+	ScancodeB:            "ScancodeB",
+	ScancodeC:            "ScancodeC",
+	ScancodeD:            "ScancodeD",
+	ScancodeE:            "ScancodeE",
+	ScancodeF:            "ScancodeF",
+	ScancodeG:            "ScancodeG",
+	ScancodeH:            "ScancodeH",
+	ScancodeI:            "ScancodeI",
+	ScancodeJ:            "ScancodeJ",
+	ScancodeK:            "ScancodeK",
+	ScancodeL:            "ScancodeL",
+	ScancodeM:            "ScancodeM",
+	ScancodeN:            "ScancodeN",
+	ScancodeO:            "ScancodeO",
+	ScancodeP:            "ScancodeP",
+	ScancodeQ:            "ScancodeQ",
+	ScancodeR:            "ScancodeR",
+	ScancodeS:            "ScancodeS",
+	ScancodeT:            "ScancodeT",
+	ScancodeU:            "ScancodeU",
+	ScancodeV:            "ScancodeV",
+	ScancodeW:            "ScancodeW",
+	ScancodeX:            "ScancodeX",
+	ScancodeY:            "ScancodeY",
+	ScancodeZ:            "ScancodeZ",
+	Scancode1:            "Scancode1",
+	Scancode2:            "Scancode2",
+	Scancode3:            "Scancode3",
+	Scancode4:            "Scancode4",
+	Scancode5:            "Scancode5",
+	Scancode6:            "Scancode6",
+	Scancode7:            "Scancode7",
+	Scancode8:            "Scancode8",
+	Scancode9:            "Scancode9",
+	Scancode0:            "Scancode0",
+	ScancodeReturn:       "ScancodeReturn",
+	ScancodeEscape:       "ScancodeEscape",
+	ScancodeBackspace:    "ScancodeBackspace",
+	ScancodeTab:          "ScancodeTab",
+	ScancodeSpace:        "ScancodeSpace",
+	ScancodeMinus:        "ScancodeMinus",
+	ScancodeEquals:       "ScancodeEquals",
+	ScancodeLeftBracket:  "ScancodeLeftBracket",
+	ScancodeRightBracket: "ScancodeRightBracket",
+	ScancodeBackslash:    "ScancodeBackslash",
+	ScancodeSemicolon:    "ScancodeSemicolon",
+	ScancodeApostrophe:   "ScancodeApostrophe",
+	ScancodeGrave:        "ScancodeGrave",
+	ScancodeComma:        "ScancodeComma",
+	ScancodePeriod:       "ScancodePeriod",
+	ScancodeSlash:        "ScancodeSlash",
+	ScancodeCapsLock:     "ScancodeCapsLock",
+	ScancodeF1:           "ScancodeF1",
+	ScancodeF2:           "ScancodeF2",
+	ScancodeF3:           "ScancodeF3",
+	ScancodeF4:           "ScancodeF4",
+	ScancodeF5:           "ScancodeF5",
+	ScancodeF6:           "ScancodeF6",
+	ScancodeF7:           "ScancodeF7",
+	ScancodeF8:           "ScancodeF8",
+	ScancodeF9:           "ScancodeF9",
+	ScancodeF10:          "ScancodeF10",
+	ScancodeF11:          "ScancodeF11",
+	ScancodeF12:          "ScancodeF12",
+	ScancodePrintScreen:  "ScancodePrintScreen",
+	ScancodeScrollLock:   "ScancodeScrollLock",
+	ScancodePause:        "ScancodePause",
+	ScancodeInsert:       "ScancodeInsert",
+	ScancodeHome:         "ScancodeHome",
+	ScancodePageUp:       "ScancodePageUp",
+	ScancodeDelete:       "ScancodeDelete",
+	ScancodeEnd:          "ScancodeEnd",
+	ScancodePageDown:     "ScancodePageDown",
+	ScancodeRight:        "ScancodeRight",
+	ScancodeLeft:         "ScancodeLeft",
+	ScancodeDown:         "ScancodeDown",
+	ScancodeUp:           "ScancodeUp",
+	ScancodeNumLockClear: "ScancodeNumLockClear",
+	ScancodeKPDivide:     "ScancodeKPDivide",
+	ScancodeKPMultiply:   "ScancodeKPMultiply",
+	ScancodeKPMinus:      "ScancodeKPMinus",
+	ScancodeKPPlus:       "ScancodeKPPlus",
+	ScancodeKPEnter:      "ScancodeKPEnter",
+	ScancodeKP1:          "ScancodeKP1",
+	ScancodeKP2:          "ScancodeKP2",
+	ScancodeKP3:          "ScancodeKP3",
+	ScancodeKP4:          "ScancodeKP4",
+	ScancodeKP5:          "ScancodeKP5",
+	ScancodeKP6:          "ScancodeKP6",
+	ScancodeKP7:          "ScancodeKP7",
+	ScancodeKP8:          "ScancodeKP8",
+	ScancodeKP9:          "ScancodeKP9",
+	ScancodeKP0:          "ScancodeKP0",
+	ScancodeKPPeriod:     "ScancodeKPPeriod",
+	ScancodeLCtrl:        "ScancodeLCtrl",
+	ScancodeLShift:       "ScancodeLShift",
+	ScancodeLAlt:         "ScancodeLAlt",
+	ScancodeLGUI:         "ScancodeLGUI",
+	ScancodeRCtrl:        "ScancodeRCtrl",
+	ScancodeRShift:       "ScancodeRShift",
+	ScancodeRAlt:         "ScancodeRAlt",
+	ScancodeRGUI:         "ScancodeRGUI",
+}
+
+func (s Scancode) String() string {
+	if n, ok := scancodeNames[s]; ok {
+		return n
+	}
+	return "Unknown(" + strconv.Itoa(int(s)) + ")"
+}
+
+// Key returns the key at the scancode's position in the current keyboard layout.
+func (s Scancode) Key() Key {
+	return Key(C.SDL_GetKeyFromScancode(C.SDL_Scancode(s)))
+}
+
+// Scancode returns the scancode of the key in the current keyboard layout.
+func (k Key) Scancode() Scancode {
+	return Scancode(C.SDL_GetScancodeFromKey(C.SDL_Keycode(k)))
+}
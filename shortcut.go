@@ -0,0 +1,165 @@
+// © 2013 the Ui Authors under the MIT license. See AUTHORS for the list of authors.
+
+package ui
+
+/*
+#include "ui.h"
+*/
+import "C"
+
+import (
+	"errors"
+	"strings"
+	"sync"
+	"unsafe"
+)
+
+// A Shortcut is a key pressed together with modifier keys, such as Ctrl+Shift+S.
+//
+// The string form of a Shortcut, returned by String and accepted by ParseShortcut,
+// is suitable for showing to users and for storing in configuration files.
+// Shortcut implements encoding.TextMarshaler and encoding.TextUnmarshaler using it.
+type Shortcut struct {
+	Key Key
+	Mod KeyMod
+}
+
+// ShortcutMods are the modifiers of a Shortcut, in canonical order, with their names.
+var shortcutMods = []struct {
+	mod  KeyMod
+	name string
+}{
+	{ModCtrl, "Ctrl"},
+	{ModAlt, "Alt"},
+	{ModShift, "Shift"},
+	{ModGUI, "GUI"},
+	{ModMode, "AltGr"},
+}
+
+// ModAliases maps lower-case modifier names accepted by ParseShortcut to modifiers.
+var modAliases = map[string]KeyMod{
+	"ctrl":    ModCtrl,
+	"control": ModCtrl,
+	"alt":     ModAlt,
+	"option":  ModAlt,
+	"opt":     ModAlt,
+	"shift":   ModShift,
+	"gui":     ModGUI,
+	"cmd":     ModGUI,
+	"command": ModGUI,
+	"super":   ModGUI,
+	"meta":    ModGUI,
+	"win":     ModGUI,
+	"altgr":   ModMode,
+}
+
+// ShortcutMod returns the modifiers of mod that are meaningful for shortcuts,
+// with left and right modifiers not distinguished.
+func shortcutMod(mod KeyMod) KeyMod {
+	var m KeyMod
+	for _, sm := range shortcutMods {
+		if mod&sm.mod != 0 {
+			m |= sm.mod
+		}
+	}
+	return m
+}
+
+// String returns the shortcut's name, such as "Ctrl+Shift+S".
+// Modifiers are listed in the order Ctrl, Alt, Shift, GUI and AltGr, without
+// distinguishing between left and right.  The key is named by Key.Name,
+// so the user interface must be running.
+func (s Shortcut) String() string {
+	var parts []string
+	for _, sm := range shortcutMods {
+		if s.Mod&sm.mod != 0 {
+			parts = append(parts, sm.name)
+		}
+	}
+	return strings.Join(append(parts, s.Key.Name()), "+")
+}
+
+// Matches returns whether the keyboard event is a press of the shortcut.
+// Left and right modifiers are not distinguished, and lock keys, such as
+// Caps Lock and Num Lock, are ignored.
+func (s Shortcut) Matches(ev *KeyboardEvent) bool {
+	return ev.Down && ev.Key == s.Key && shortcutMod(ev.Mod) == shortcutMod(s.Mod)
+}
+
+// MarshalText implements encoding.TextMarshaler.
+// It returns an error if the key has no name, since ParseShortcut could not parse the result.
+func (s Shortcut) MarshalText() ([]byte, error) {
+	if s.Key.Name() == "" {
+		return nil, errors.New("ui: key " + s.Key.String() + " has no name")
+	}
+	return []byte(s.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (s *Shortcut) UnmarshalText(text []byte) error {
+	k, m, err := ParseShortcut(string(text))
+	if err != nil {
+		return err
+	}
+	s.Key, s.Mod = k, m
+	return nil
+}
+
+// ParseShortcut parses a shortcut, such as "Ctrl+Shift+S", returning its key and modifiers.
+// It accepts the form returned by Shortcut.String.  Modifier names are case-insensitive,
+// and the aliases Control, Option, Cmd, Command, Super, Meta and Win are also accepted.
+// The key is parsed by ParseKey.
+func ParseShortcut(s string) (Key, KeyMod, error) {
+	var mod KeyMod
+	rest := s
+	for len(rest) > 1 {
+		// Search from the second byte, so that a key named "+" is not taken for a separator.
+		i := strings.IndexByte(rest[1:], '+') + 1
+		if i == 0 {
+			break
+		}
+		m, ok := modAliases[strings.ToLower(strings.TrimSpace(rest[:i]))]
+		if !ok {
+			break
+		}
+		mod |= m
+		rest = rest[i+1:]
+	}
+	k, err := ParseKey(strings.TrimSpace(rest))
+	if err != nil {
+		return 0, 0, err
+	}
+	return k, mod, nil
+}
+
+// KeyNameMu serializes calls to SDL_GetKeyName, which returns a static buffer.
+var keyNameMu sync.Mutex
+
+// Name returns the key's user-facing name, such as "S", "Space" or "Left Ctrl",
+// as reported by SDL_GetKeyName.  The name of an unknown key is "".
+// SDL names keys using the current keyboard layout, so the user interface must be
+// running; before it starts, some keys, such as F1, have no name.
+func (k Key) Name() string {
+	keyNameMu.Lock()
+	defer keyNameMu.Unlock()
+	return C.GoString(C.SDL_GetKeyName(C.SDL_Keycode(k)))
+}
+
+// ParseKey returns the key with the given name.  It accepts the user-facing
+// names returned by Key.Name, case-insensitively, and the names returned by
+// Key.String, such as "KeyS".
+func ParseKey(name string) (Key, error) {
+	for k, n := range keyNames {
+		if n == name && k != KeyUnknown {
+			return k, nil
+		}
+	}
+	if name != "" {
+		cname := C.CString(name)
+		defer C.free(unsafe.Pointer(cname))
+		if k := Key(C.SDL_GetKeyFromName(cname)); k != KeyUnknown {
+			return k, nil
+		}
+	}
+	return KeyUnknown, &ParseError{Kind: "key", Text: name}
+}
@@ -0,0 +1,138 @@
+// © 2013 the Ui Authors under the MIT license. See AUTHORS for the list of authors.
+
+package ui
+
+import "testing"
+
+// ModCombinations returns every combination of the shortcut modifiers.
+func modCombinations() []KeyMod {
+	var mods []KeyMod
+	for i := 0; i < 1<<uint(len(shortcutMods)); i++ {
+		var m KeyMod
+		for j, sm := range shortcutMods {
+			if i&(1<<uint(j)) != 0 {
+				m |= sm.mod
+			}
+		}
+		mods = append(mods, m)
+	}
+	return mods
+}
+
+func TestShortcutRoundTrip(t *testing.T) {
+	for k := range keyNames {
+		if k.Name() == "" {
+			// Keys without a name, such as KeyUnknown, have no string form.
+			continue
+		}
+		for _, m := range modCombinations() {
+			s := Shortcut{Key: k, Mod: m}
+			str := s.String()
+			pk, pm, err := ParseShortcut(str)
+			if err != nil {
+				t.Errorf("ParseShortcut(%q) [%v]: %v", str, k, err)
+				continue
+			}
+			// Distinct keys may share a name, such as Return and Return2,
+			// so it is the string form that must round-trip.
+			p := Shortcut{Key: pk, Mod: pm}
+			if got := p.String(); got != str || shortcutMod(pm) != shortcutMod(m) {
+				t.Errorf("ParseShortcut(%q) [%v] = %v, %v; String() = %q", str, k, pk, pm, got)
+			}
+		}
+	}
+}
+
+func TestShortcutString(t *testing.T) {
+	tests := []struct {
+		s    Shortcut
+		want string
+	}{
+		{Shortcut{KeyS, ModCtrl | ModShift}, "Ctrl+Shift+S"},
+		{Shortcut{KeyS, ModShift | ModCtrl}, "Ctrl+Shift+S"},
+		{Shortcut{KeyS, ModLCtrl}, "Ctrl+S"},
+		{Shortcut{KeyS, ModRCtrl | ModLShift}, "Ctrl+Shift+S"},
+		{Shortcut{KeyF1, ModGUI | ModAlt}, "Alt+GUI+F1"},
+		{Shortcut{KeyF1, ModMode | ModCtrl}, "Ctrl+AltGr+F1"},
+		{Shortcut{KeySpace, ModNone}, "Space"},
+		{Shortcut{Key('+'), ModCtrl}, "Ctrl++"},
+		{Shortcut{KeyKPPlus, ModCtrl}, "Ctrl+Keypad +"},
+	}
+	for _, test := range tests {
+		if got := test.s.String(); got != test.want {
+			t.Errorf("Shortcut{%v, %v}.String() = %q, want %q", test.s.Key, test.s.Mod, got, test.want)
+		}
+	}
+}
+
+func TestParseShortcut(t *testing.T) {
+	tests := []struct {
+		s   string
+		key Key
+		mod KeyMod
+	}{
+		{"Ctrl+Shift+S", KeyS, ModCtrl | ModShift},
+		{"ctrl+shift+s", KeyS, ModCtrl | ModShift},
+		{"CTRL+SHIFT+S", KeyS, ModCtrl | ModShift},
+		{"Shift+Ctrl+S", KeyS, ModCtrl | ModShift},
+		{" Ctrl + S ", KeyS, ModCtrl},
+		{"Control+S", KeyS, ModCtrl},
+		{"Option+S", KeyS, ModAlt},
+		{"Opt+S", KeyS, ModAlt},
+		{"Cmd+S", KeyS, ModGUI},
+		{"Command+S", KeyS, ModGUI},
+		{"Super+S", KeyS, ModGUI},
+		{"Meta+S", KeyS, ModGUI},
+		{"Win+S", KeyS, ModGUI},
+		{"AltGr+S", KeyS, ModMode},
+		{"Ctrl+Ctrl+S", KeyS, ModCtrl},
+		{"S", KeyS, ModNone},
+		{"KeyS", KeyS, ModNone},
+		{"Ctrl+KeyS", KeyS, ModCtrl},
+		{"space", KeySpace, ModNone},
+		{"+", Key('+'), ModNone},
+		{"Ctrl++", Key('+'), ModCtrl},
+		{"Ctrl+Shift++", Key('+'), ModCtrl | ModShift},
+		{"Keypad +", KeyKPPlus, ModNone},
+		{"Ctrl+Keypad +", KeyKPPlus, ModCtrl},
+		{"Left Ctrl", KeyLCtrl, ModNone},
+		{"Shift+Left Ctrl", KeyLCtrl, ModShift},
+	}
+	for _, test := range tests {
+		k, m, err := ParseShortcut(test.s)
+		if err != nil || k != test.key || m != test.mod {
+			t.Errorf("ParseShortcut(%q) = %v, %v, %v, want %v, %v, nil", test.s, k, m, err, test.key, test.mod)
+		}
+	}
+}
+
+func TestParseShortcutError(t *testing.T) {
+	for _, s := range []string{"", "Ctrl+", "Ctrl+Shift+", "Hyper+S", "Ctrl+NoSuchKey", "Ctrl+S+Shift"} {
+		k, m, err := ParseShortcut(s)
+		if _, ok := err.(*ParseError); !ok {
+			t.Errorf("ParseShortcut(%q) = %v, %v, %v, want a *ParseError", s, k, m, err)
+		}
+	}
+}
+
+func TestShortcutTextUnnamed(t *testing.T) {
+	s := Shortcut{KeyUnknown, ModCtrl}
+	if text, err := s.MarshalText(); err == nil {
+		t.Errorf("MarshalText of an unnamed key = %q, want an error", text)
+	}
+}
+
+func TestShortcutText(t *testing.T) {
+	s := Shortcut{KeyZ, ModCtrl | ModShift}
+	text, err := s.MarshalText()
+	if err != nil {
+		t.Fatalf("MarshalText: %v", err)
+	}
+	var got Shortcut
+	if err := got.UnmarshalText(text); err != nil {
+		t.Fatalf("UnmarshalText(%q): %v", text, err)
+	}
+	if got != s {
+		t.Errorf("UnmarshalText(%q) = %v, want %v", text, got, s)
+	}
+}
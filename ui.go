@@ -5,84 +5,339 @@ package ui
 
 /*
 #include "ui.h"
+
+static int pushEvent(Uint32 type) {
+	SDL_Event ev;
+	SDL_zero(ev);
+	ev.type = type;
+	return SDL_PushEvent(&ev);
+}
 */
 import "C"
 
 import (
-	"errors"
+	"context"
+	"image"
 	"os"
+	"os/signal"
 	"runtime"
+	"sync"
+	"syscall"
 	"time"
 	"unsafe"
 )
 
 func init() {
 	runtime.LockOSThread()
+	mainThread = C.SDL_ThreadID()
 }
 
-const eventChanSize = 100
-
 var (
-	doChan  = make(chan func(), 1)
 	windows = make(map[windowID]*Window, 1)
+
+	// AppEvents is the queue of application events, returned by Events.
+	appEvents = newEventQueue(EventOptions{})
+
+	// WakeEvent is the type of the SDL event pushed to wake the main loop.
+	wakeEvent C.Uint32
+
+	// UserEvent is the type of the SDL event pushed by Window.Post.
+	userEvent C.Uint32
+
+	// Mu protects queue and running.
+	mu sync.Mutex
+
+	// Queue is the list of functions queued by do, waiting to be called by the main loop.
+	queue []func()
+
+	// Running is whether the user interface is running.
+	running bool
+
+	// MainThread is the ID of the thread to which the main go routine is locked.
+	mainThread C.SDL_threadID
+
+	// Headless is whether the running user interface is headless.
+	headless bool
 )
 
+// Options are options for running the user interface.
+type Options struct {
+	// Rate is the longest that the user interface sleeps before checking for events,
+	// as described for Start.  If Rate is zero, the user interface sleeps until an
+	// event arrives.
+	Rate time.Duration
+
+	// Headless runs the user interface without a display or GPU.  It uses SDL's
+	// dummy video and audio drivers and its software renderer, so windows are never
+	// shown, but they can be created and drawn to as usual.
+	//
+	// Headless sets the SDL_VIDEODRIVER, SDL_AUDIODRIVER and SDL_RENDER_DRIVER
+	// environment variables of the process.
+	Headless bool
+
+	// Signals delivers interrupt and termination signals, such as from Ctrl+C,
+	// as a QuitEvent on the channel returned by Events, instead of terminating
+	// the program.
+	Signals bool
+}
+
 // Start starts the user interface.  It must be called by the main go routine, and it
 // never returns. The function f is called in a new go routine as the new "main"
-// function.  Rate is the rate at which the user interface should poll for events.
-func Start(f func(), rate time.Duration) {
-	if C.SDL_Init(C.SDL_INIT_EVERYTHING) < 0 {
-		panic(sdlError())
+// function.  When f returns, the user interface is shut down and the program exits.
+//
+// The user interface sleeps until an event arrives or work is requested by another go
+// routine.  If a rate is given, it is the longest that the user interface sleeps before
+// checking for events; this is only needed on systems where SDL cannot wake the user
+// interface when events arrive.
+//
+// Start panics if the user interface cannot be initialized.
+func Start(f func(), rate ...time.Duration) {
+	if err := StartErr(f, rate...); err != nil {
+		panic(err)
 	}
+}
+
+// StartErr is like Start, but it returns an error if the user interface
+// cannot be initialized.  If initialization succeeds, StartErr never returns.
+func StartErr(f func(), rate ...time.Duration) error {
+	err := Run(context.Background(), func(context.Context) error {
+		f()
+		return nil
+	}, rate...)
+	if err != nil {
+		return err
+	}
+	os.Exit(0)
+	return nil
+}
+
+// StartHeadless is like Start, but it runs the user interface headless,
+// as described for Options.Headless.
+func StartHeadless(f func()) {
+	err := RunWithOptions(context.Background(), func(context.Context) error {
+		f()
+		return nil
+	}, Options{Headless: true})
+	if err != nil {
+		panic(err)
+	}
+	os.Exit(0)
+}
+
+// Run starts the user interface and returns when it is shut down.  Like Start, it must be
+// called by the main go routine.  The function f is called in a new go routine as the new
+// "main" function.  The optional rate is as described for Start.
+//
+// Run returns the error returned by f when f returns, or the context's error when ctx is
+// done.  The context passed to f is cancelled when Run returns.  Before returning, Run
+// destroys all windows, closes the audio device, frees all cached sounds, and quits SDL.
+// After Run returns, functions that require the user interface return ErrNotRunning.
+func Run(ctx context.Context, f func(context.Context) error, rate ...time.Duration) error {
+	var opts Options
+	if len(rate) > 0 {
+		opts.Rate = rate[0]
+	}
+	return RunWithOptions(ctx, f, opts)
+}
 
+// RunWithOptions is like Run, but the user interface is configured by opts.
+func RunWithOptions(ctx context.Context, f func(context.Context) error, opts Options) error {
+	if opts.Headless {
+		setenv("SDL_VIDEODRIVER", "dummy")
+		setenv("SDL_AUDIODRIVER", "dummy")
+		setenv("SDL_RENDER_DRIVER", "software")
+	}
+	if C.SDL_Init(C.SDL_INIT_EVERYTHING) < 0 {
+		return sdlError("SDL_Init")
+	}
+	if wakeEvent == 0 {
+		if wakeEvent = C.SDL_RegisterEvents(2); wakeEvent == ^C.Uint32(0) {
+			wakeEvent = 0
+			C.SDL_Quit()
+			return sdlError("SDL_RegisterEvents")
+		}
+		userEvent = wakeEvent + 1
+	}
 	initAudio()
+	headless = opts.Headless
+	mu.Lock()
+	running = true
+	mu.Unlock()
+	defer shutdown()
+
+	timeout := C.int(-1)
+	if opts.Rate > 0 {
+		timeout = C.int(opts.Rate / time.Millisecond)
+		if timeout == 0 {
+			timeout = 1
+		}
+	}
 
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	fErr := make(chan error, 1)
 	go func() {
-		f()
-		os.Exit(0)
+		fErr <- f(ctx)
+		wake()
+	}()
+	go func() {
+		<-ctx.Done()
+		wake()
 	}()
+	if opts.Signals {
+		sigs := make(chan os.Signal, 1)
+		signal.Notify(sigs, os.Interrupt, syscall.SIGTERM)
+		defer signal.Stop(sigs)
+		go func() {
+			for {
+				select {
+				case <-sigs:
+					push(C.SDL_QUIT)
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+	}
 
-	tick := time.NewTicker(rate)
 	for {
+		runQueue()
 		select {
-		case f := <-doChan:
-			f()
-		case <-tick.C:
-			pollEvents()
+		case err := <-fErr:
+			return err
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+		}
+		var b eventBatch
+		if ev := waitEvent(timeout); ev != nil {
+			b.add(ev)
 		}
+		for ev := pollEvent(); ev != nil; ev = pollEvent() {
+			b.add(ev)
+		}
+		b.flush()
+	}
+}
+
+// Shutdown releases all resources held by the user interface and quits SDL.
+// It must be called from the main go routine.
+func shutdown() {
+	mu.Lock()
+	running = false
+	mu.Unlock()
+	runQueue()
+
+	for _, win := range windows {
+		win.destroy()
 	}
+	closeAudio()
+	C.SDL_Quit()
+
+	// Values posted but never delivered are dropped.
+	userMu.Lock()
+	userValues = make(map[C.Sint32]interface{})
+	userMu.Unlock()
 }
 
-func do(f func()) {
+// Setenv sets an environment variable in a way that is visible to SDL.
+func setenv(name, value string) {
+	cname := C.CString(name)
+	defer C.free(unsafe.Pointer(cname))
+	cvalue := C.CString(value)
+	defer C.free(unsafe.Pointer(cvalue))
+	C.SDL_setenv(cname, cvalue, 1)
+}
+
+// Do calls f from the main go routine and waits for it to return.
+// If the user interface is not running, f is not called and ErrNotRunning is returned.
+// If do is called from the main go routine, such as from a function passed to
+// Window.Draw, f is called directly, since the main loop cannot run the queue
+// until the caller returns.
+func do(f func()) error {
 	done := make(chan struct{})
-	doChan <- func() {
+	mu.Lock()
+	if !running {
+		mu.Unlock()
+		return ErrNotRunning
+	}
+	if C.SDL_ThreadID() == mainThread {
+		mu.Unlock()
 		f()
-		done <- struct{}{}
+		return nil
 	}
+	queue = append(queue, func() {
+		f()
+		close(done)
+	})
+	C.pushEvent(wakeEvent)
+	mu.Unlock()
 	<-done
+	return nil
+}
+
+// DoErr is like do, but it returns the error returned by f.
+func doErr(f func() error) error {
+	var err error
+	if e := do(func() { err = f() }); e != nil {
+		return e
+	}
+	return err
+}
+
+// Wake wakes the main loop if it is waiting for events.
+func wake() {
+	push(wakeEvent)
+}
+
+// Push pushes an SDL event of the given type, if the user interface is running.
+func push(t C.Uint32) {
+	mu.Lock()
+	defer mu.Unlock()
+	if running {
+		C.pushEvent(t)
+	}
+}
+
+// RunQueue calls all functions queued by do.
+// It must be called from the main go routine.
+func runQueue() {
+	mu.Lock()
+	q := queue
+	queue = nil
+	mu.Unlock()
+	for _, f := range q {
+		f()
+	}
 }
 
 type windowID C.Uint32
 
-type windowIDer interface {
-	windowID() windowID
+// Events returns the channel of application events: events that concern the
+// application as a whole rather than a single window, such as QuitEvent.
+// Window events are delivered on the channel returned by Window.Events.
+func Events() <-chan Event {
+	return appEvents.ch
 }
 
-func pollEvents() {
-	for {
-		if ev := pollEvent(); ev == nil {
-			break
-		} else if e, ok := ev.(windowIDer); ok {
-			win, ok := windows[e.windowID()]
-			if !ok {
-				return
-			}
-			select {
-			case win.events <- e:
-			default: // too many events queued, junk it.
-			}
-		}
+// DroppedEvents returns the number of application events that have been
+// discarded because the channel returned by Events was full.
+func DroppedEvents() uint64 {
+	return appEvents.droppedEvents()
+}
+
+// SendEvent sends an event to the channel of its window,
+// or to the application's channel if it is an application event.
+func sendEvent(ev Event) {
+	if _, ok := ev.(appEvent); ok {
+		appEvents.send(ev)
+		return
+	}
+	win := ev.Window()
+	if win == nil || win.destroyed {
+		return
 	}
+	win.events.send(ev)
 }
 
 // A Window is a single window on the user's graphical interface.
@@ -90,8 +345,13 @@ type Window struct {
 	win    *C.SDL_Window
 	rend   *C.SDL_Renderer
 	id     windowID
-	events chan interface{}
+	events *eventQueue
+	done   chan struct{}
 	imgs   map[string]texture
+
+	// Destroyed is whether the window has been destroyed.
+	// It is only accessed from the main go routine.
+	destroyed bool
 }
 
 type texture struct {
@@ -100,69 +360,236 @@ type texture struct {
 }
 
 // NewWindow returns a new window.
+// It panics if the window cannot be created.
 func NewWindow(title string, w, h int) *Window {
+	win, err := NewWindowErr(title, w, h)
+	if err != nil {
+		panic(err)
+	}
+	return win
+}
+
+// NewWindowErr is like NewWindow, but it returns an error if the window cannot be created.
+func NewWindowErr(title string, w, h int) (*Window, error) {
+	return NewWindowWithOptions(title, w, h, WindowOptions{})
+}
+
+// WindowOptions are options for creating a window.
+// The zero value creates a shown window at an undefined position.
+type WindowOptions struct {
+	// Renderer selects the renderer used to draw to the window.
+	Renderer RendererOptions
+
+	// Resizable allows the user to resize the window.
+	Resizable bool
+
+	// Fullscreen makes the window fullscreen, changing the display mode to the window's size.
+	Fullscreen bool
+
+	// FullscreenDesktop makes the window fullscreen at the desktop's resolution,
+	// without changing the display mode.  It takes precedence over Fullscreen.
+	FullscreenDesktop bool
+
+	// Borderless creates the window without decorations.
+	Borderless bool
+
+	// Hidden creates the window hidden.
+	Hidden bool
+
+	// AlwaysOnTop keeps the window above all other windows.
+	AlwaysOnTop bool
+
+	// HighDPI creates the window in high-DPI mode on systems that support it.
+	// The canvas size may then be larger than the window size; see Canvas.Size.
+	HighDPI bool
+
+	// Position, if non-nil, is the position of the window's upper-left corner, in
+	// screen coordinates.  If Position is nil, the window is centered if Centered
+	// is set, or is placed by the system otherwise.
+	Position *image.Point
+
+	// Centered centers the window on the screen if Position is nil.
+	Centered bool
+
+	// MinSize and MaxSize, if non-zero, are the minimum and maximum size of the
+	// window, limiting how the user can resize it.
+	MinSize, MaxSize image.Point
+
+	// Events configures the buffering of the window's event channel.
+	Events EventOptions
+}
+
+func (opts *WindowOptions) flags() C.Uint32 {
+	flags := C.Uint32(C.SDL_WINDOW_SHOWN | C.SDL_WINDOW_OPENGL)
+	if headless {
+		// The dummy video driver has no OpenGL.
+		flags = C.SDL_WINDOW_SHOWN
+	}
+	if opts.Hidden {
+		flags = flags&^C.SDL_WINDOW_SHOWN | C.SDL_WINDOW_HIDDEN
+	}
+	if opts.Resizable {
+		flags |= C.SDL_WINDOW_RESIZABLE
+	}
+	if opts.FullscreenDesktop {
+		flags |= C.SDL_WINDOW_FULLSCREEN_DESKTOP
+	} else if opts.Fullscreen {
+		flags |= C.SDL_WINDOW_FULLSCREEN
+	}
+	if opts.Borderless {
+		flags |= C.SDL_WINDOW_BORDERLESS
+	}
+	if opts.AlwaysOnTop {
+		flags |= C.SDL_WINDOW_ALWAYS_ON_TOP
+	}
+	if opts.HighDPI {
+		flags |= C.SDL_WINDOW_ALLOW_HIGHDPI
+	}
+	return flags
+}
+
+func (opts *WindowOptions) position() (x, y C.int) {
+	switch {
+	case opts.Position != nil:
+		return C.int(opts.Position.X), C.int(opts.Position.Y)
+	case opts.Centered:
+		return C.SDL_WINDOWPOS_CENTERED, C.SDL_WINDOWPOS_CENTERED
+	}
+	return C.SDL_WINDOWPOS_UNDEFINED, C.SDL_WINDOWPOS_UNDEFINED
+}
+
+// NewWindowWithOptions is like NewWindowErr, but the window is configured by opts.
+func NewWindowWithOptions(title string, w, h int, opts WindowOptions) (*Window, error) {
 	win := &Window{
-		events: make(chan interface{}, eventChanSize),
+		events: newEventQueue(opts.Events),
+		done:   make(chan struct{}),
 		imgs:   make(map[string]texture),
 	}
-	do(func() {
+	err := doErr(func() error {
 		ctitle := C.CString(title)
 		defer C.free(unsafe.Pointer(ctitle))
-		x, y := C.SDL_WINDOWPOS_UNDEFINED, C.SDL_WINDOWPOS_UNDEFINED
-		flags := C.SDL_WINDOW_SHOWN | C.SDL_WINDOW_OPENGL
-
-		win.win = C.SDL_CreateWindow(ctitle, C.int(x), C.int(y), C.int(w), C.int(h), C.Uint32(flags))
+		x, y := opts.position()
+		win.win = C.SDL_CreateWindow(ctitle, x, y, C.int(w), C.int(h), opts.flags())
 		if win.win == nil {
-			panic(sdlError())
+			return sdlError("SDL_CreateWindow")
+		}
+		if opts.MinSize != (image.Point{}) {
+			C.SDL_SetWindowMinimumSize(win.win, C.int(opts.MinSize.X), C.int(opts.MinSize.Y))
+		}
+		if opts.MaxSize != (image.Point{}) {
+			C.SDL_SetWindowMaximumSize(win.win, C.int(opts.MaxSize.X), C.int(opts.MaxSize.Y))
 		}
 
-		win.rend = C.SDL_CreateRenderer(win.win, 0, C.SDL_RENDERER_ACCELERATED)
-		if win.rend == nil {
-			panic(sdlError())
+		var err error
+		if win.rend, err = newRenderer(win.win, opts.Renderer); err != nil {
+			C.SDL_DestroyWindow(win.win)
+			return err
 		}
 		if C.SDL_SetRenderDrawBlendMode(win.rend, C.SDL_BLENDMODE_BLEND) < 0 {
-			panic(sdlError())
+			err = sdlError("SDL_SetRenderDrawBlendMode")
+			C.SDL_DestroyRenderer(win.rend)
+			C.SDL_DestroyWindow(win.win)
+			return err
 		}
 
 		win.id = windowID(C.SDL_GetWindowID(win.win))
 		windows[win.id] = win
+		return nil
 	})
-	return win
+	if err != nil {
+		return nil, err
+	}
+	return win, nil
 }
 
-// Destroy destroys the window.
-func (win *Window) Destroy() {
-	do(func() {
-		C.SDL_DestroyRenderer(win.rend)
-		C.SDL_DestroyWindow(win.win)
-		delete(windows, win.id)
+// Do is like doErr, but it returns ErrDestroyed without calling f
+// if the window has been destroyed.
+func (win *Window) do(f func() error) error {
+	return doErr(func() error {
+		if win.destroyed {
+			return ErrDestroyed
+		}
+		return f()
 	})
 }
 
-// FlushCache flushes any cached textures on this window.
-func (win *Window) FlushCache() {
-	do(func() {
-		for _, img := range win.imgs {
-			C.SDL_DestroyTexture(img.tex)
+// Destroy destroys the window, freeing its cached textures and closing its event channel.
+// Destroying a window that is already destroyed has no effect.
+//
+// Once the window is destroyed, its methods return ErrDestroyed,
+// or zero values for methods that do not return an error.
+func (win *Window) Destroy() error {
+	select {
+	case <-win.done:
+		return nil
+	default:
+	}
+	return do(win.destroy)
+}
+
+// Destroy destroys the window if it is not already destroyed.
+// It must be called from the main go routine.
+func (win *Window) destroy() {
+	if win.destroyed {
+		return
+	}
+	win.destroyed = true
+	win.flushCache()
+	C.SDL_DestroyRenderer(win.rend)
+	C.SDL_DestroyWindow(win.win)
+	delete(windows, win.id)
+	delete(pendingDrops, win.id)
+	for t, w := range touchWindows {
+		if w == win {
+			delete(touchWindows, t)
 		}
-		win.imgs = make(map[string]texture)
+	}
+	close(win.events.ch)
+	close(win.done)
+}
+
+// Done returns a channel that is closed when the window is destroyed.
+func (win *Window) Done() <-chan struct{} {
+	return win.done
+}
+
+// FlushCache flushes any cached textures on this window.
+func (win *Window) FlushCache() error {
+	return win.do(func() error {
+		win.flushCache()
+		return nil
 	})
 }
 
+func (win *Window) flushCache() {
+	for _, img := range win.imgs {
+		C.SDL_DestroyTexture(img.tex)
+	}
+	win.imgs = make(map[string]texture)
+}
+
 // Events returns the event channel for the window.
-func (win *Window) Events() <-chan interface{} {
-	return win.events
+// The channel is closed when the window is destroyed.
+func (win *Window) Events() <-chan Event {
+	return win.events.ch
+}
+
+// DroppedEvents returns the number of events for the window that have been
+// discarded because its event channel was full.
+func (win *Window) DroppedEvents() uint64 {
+	return win.events.droppedEvents()
 }
 
 // Draw calls f from the main go routine. F is passed a canvas which can draw to the
 // window. The Canvas's methods can only safely be called from the main go routine.
-func (win *Window) Draw(f func(win Canvas)) {
-	do(func() {
+//
+// The main loop waits for f to return, so f must not wait for events or for other
+// go routines that use the user interface.  F may call the other functions of this
+// package, such as PlayWAV or Sound.Stop, but not Draw, of this or any other window.
+func (win *Window) Draw(f func(win Canvas)) error {
+	return win.do(func() error {
 		f(Canvas{win: win})
 		C.SDL_RenderPresent(win.rend)
+		return nil
 	})
 }
-
-func sdlError() error {
-	return errors.New(C.GoString(C.SDL_GetError()))
-}
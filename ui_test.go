@@ -0,0 +1,374 @@
+// © 2013 the Ui Authors under the MIT license. See AUTHORS for the list of authors.
+
+package ui_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/velour/ui"
+	"github.com/velour/ui/uitest"
+)
+
+const (
+	gopherPNG = "resrc/gopher.png"
+	fontTTF   = "resrc/prstartk.ttf"
+)
+
+var (
+	black = color.NRGBA{0, 0, 0, 255}
+	white = color.NRGBA{255, 255, 255, 255}
+	red   = color.NRGBA{255, 0, 0, 255}
+)
+
+// TestMain runs the tests with a headless user interface, like uitest.Main,
+// and then checks that the package reports ErrNotRunning once it has shut down.
+func TestMain(m *testing.M) {
+	code := 0
+	err := ui.RunWithOptions(context.Background(), func(context.Context) error {
+		code = m.Run()
+		return nil
+	}, ui.Options{Headless: true})
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "RunWithOptions:", err)
+		os.Exit(1)
+	}
+	if _, err := ui.NewWindowErr("after", 8, 8); err != ui.ErrNotRunning {
+		fmt.Fprintf(os.Stderr, "NewWindowErr after Run returned: got %v, want %v\n", err, ui.ErrNotRunning)
+		code = 1
+	}
+	if err := ui.LoadFont(fontTTF); err != ui.ErrNotRunning {
+		fmt.Fprintf(os.Stderr, "LoadFont after Run returned: got %v, want %v\n", err, ui.ErrNotRunning)
+		code = 1
+	}
+	if _, err := ui.PlayWAVErr("resrc/pew.wav", false); err != ui.ErrNotRunning {
+		fmt.Fprintf(os.Stderr, "PlayWAVErr after Run returned: got %v, want %v\n", err, ui.ErrNotRunning)
+		code = 1
+	}
+	os.Exit(code)
+}
+
+// Render draws with f on a new black canvas, and returns the drawn pixels.
+// F is called from the main go routine, so it must not call t.Fatal.
+func render(t *testing.T, width, height int, f func(ui.Canvas)) *image.NRGBA {
+	t.Helper()
+	img, err := uitest.Render(width, height, f)
+	if err != nil {
+		t.Fatalf("Render: %v", err)
+	}
+	return img
+}
+
+// Closed returns whether ch is closed within timeout, discarding any buffered events.
+func closed(ch <-chan ui.Event, timeout time.Duration) bool {
+	t := time.After(timeout)
+	for {
+		select {
+		case _, ok := <-ch:
+			if !ok {
+				return true
+			}
+		case <-t:
+			return false
+		}
+	}
+}
+
+// CheckPixels reports an error for each of the points of img that is not the color want.
+func checkPixels(t *testing.T, img *image.NRGBA, want color.NRGBA, pts ...image.Point) {
+	t.Helper()
+	for _, p := range pts {
+		if got := img.NRGBAAt(p.X, p.Y); got != want {
+			t.Errorf("pixel %v: got %v, want %v", p, got, want)
+		}
+	}
+}
+
+func TestNewWindow(t *testing.T) {
+	win, err := ui.NewWindowErr("test", 32, 16)
+	if err != nil {
+		t.Fatalf("NewWindowErr: %v", err)
+	}
+	defer win.Destroy()
+	if win.ID() == 0 {
+		t.Errorf("ID() = 0, want non-zero")
+	}
+	if w, h := win.Size(); w != 32 || h != 16 {
+		t.Errorf("Size() = %d, %d, want 32, 16", w, h)
+	}
+
+	other := ui.NewWindow("other", 8, 8)
+	defer other.Destroy()
+	if other.ID() == win.ID() {
+		t.Errorf("two windows have ID %d", win.ID())
+	}
+}
+
+func TestDraw(t *testing.T) {
+	win, err := ui.NewWindowErr("test", 32, 16)
+	if err != nil {
+		t.Fatalf("NewWindowErr: %v", err)
+	}
+	defer win.Destroy()
+
+	called := false
+	err = win.Draw(func(c ui.Canvas) {
+		called = true
+		if w, h := c.Size(); w != 32 || h != 16 {
+			t.Errorf("Canvas.Size() = %d, %d, want 32, 16", w, h)
+		}
+	})
+	if err != nil {
+		t.Errorf("Draw: %v", err)
+	}
+	if !called {
+		t.Errorf("Draw did not call its function")
+	}
+}
+
+// TestDrawCalls checks that functions that run on the main go routine
+// can be called from a drawing function without deadlocking.
+func TestDrawCalls(t *testing.T) {
+	win, err := ui.NewWindowErr("test", 8, 8)
+	if err != nil {
+		t.Fatalf("NewWindowErr: %v", err)
+	}
+	defer win.Destroy()
+
+	err = win.Draw(func(c ui.Canvas) {
+		if err := ui.LoadFont(fontTTF); err != nil {
+			t.Errorf("LoadFont: %v", err)
+		}
+		s, err := ui.PlayWAVErr("resrc/pew.wav", false)
+		if err != nil {
+			t.Errorf("PlayWAVErr: %v", err)
+			return
+		}
+		s.Stop()
+	})
+	if err != nil {
+		t.Errorf("Draw: %v", err)
+	}
+}
+
+func TestDestroy(t *testing.T) {
+	win, err := ui.NewWindowErr("test", 8, 8)
+	if err != nil {
+		t.Fatalf("NewWindowErr: %v", err)
+	}
+	if err := win.Destroy(); err != nil {
+		t.Fatalf("Destroy: %v", err)
+	}
+
+	select {
+	case <-win.Done():
+	case <-time.After(time.Second):
+		t.Fatalf("Done not closed after Destroy")
+	}
+	if !closed(win.Events(), time.Second) {
+		t.Fatalf("Events not closed after Destroy")
+	}
+
+	if err := win.Draw(func(ui.Canvas) {}); err != ui.ErrDestroyed {
+		t.Errorf("Draw after Destroy: got %v, want %v", err, ui.ErrDestroyed)
+	}
+	if err := win.Destroy(); err != nil {
+		t.Errorf("second Destroy: %v", err)
+	}
+}
+
+func TestClear(t *testing.T) {
+	img := render(t, 4, 4, func(c ui.Canvas) {
+		c.SetColor(red)
+		c.Clear()
+	})
+	for y := 0; y < 4; y++ {
+		for x := 0; x < 4; x++ {
+			checkPixels(t, img, red, image.Pt(x, y))
+		}
+	}
+}
+
+func TestDrawPoints(t *testing.T) {
+	img := render(t, 8, 8, func(c ui.Canvas) {
+		c.SetColor(white)
+		c.DrawPoints(image.Pt(1, 1), image.Pt(6, 3))
+	})
+	checkPixels(t, img, white, image.Pt(1, 1), image.Pt(6, 3))
+	checkPixels(t, img, black, image.Pt(0, 0), image.Pt(2, 1), image.Pt(6, 4))
+}
+
+func TestDrawLines(t *testing.T) {
+	img := render(t, 8, 8, func(c ui.Canvas) {
+		c.SetColor(white)
+		c.DrawLines(image.Pt(1, 1), image.Pt(6, 1), image.Pt(6, 6))
+		c.SetColor(red)
+		c.DrawLines(image.Pt(0, 7), image.Pt(4, 3))
+	})
+	checkPixels(t, img, white, image.Pt(1, 1), image.Pt(3, 1), image.Pt(6, 1), image.Pt(6, 4), image.Pt(6, 6))
+	// SDL versions differ on whether the last point of a line is drawn, so only
+	// the start and the interior of the diagonal are checked.
+	checkPixels(t, img, red, image.Pt(0, 7), image.Pt(1, 6), image.Pt(2, 5), image.Pt(3, 4))
+	checkPixels(t, img, black, image.Pt(0, 1), image.Pt(3, 2), image.Pt(7, 6), image.Pt(1, 5), image.Pt(2, 6))
+}
+
+func TestDrawRects(t *testing.T) {
+	img := render(t, 8, 8, func(c ui.Canvas) {
+		c.SetColor(white)
+		c.DrawRects(image.Rect(1, 1, 7, 7))
+		c.SetColor(red)
+		c.DrawRects(image.Rect(2, 2, 5, 5))
+	})
+	checkPixels(t, img, white, image.Pt(1, 1), image.Pt(6, 1), image.Pt(1, 6), image.Pt(6, 6), image.Pt(3, 1))
+	checkPixels(t, img, red, image.Pt(2, 2), image.Pt(4, 2), image.Pt(2, 4), image.Pt(4, 4), image.Pt(3, 2))
+	checkPixels(t, img, black, image.Pt(0, 0), image.Pt(3, 3), image.Pt(7, 7), image.Pt(5, 5))
+}
+
+func TestFillRects(t *testing.T) {
+	img := render(t, 8, 8, func(c ui.Canvas) {
+		c.SetColor(white)
+		c.FillRects(image.Rect(1, 1, 4, 4), image.Rect(5, 5, 7, 7))
+	})
+	checkPixels(t, img, white, image.Pt(1, 1), image.Pt(3, 3), image.Pt(5, 5), image.Pt(6, 6))
+	checkPixels(t, img, black, image.Pt(0, 0), image.Pt(4, 4), image.Pt(7, 7))
+}
+
+func TestDrawPNG(t *testing.T) {
+	want, err := ui.LoadImage(gopherPNG)
+	if err != nil {
+		t.Fatalf("LoadImage: %v", err)
+	}
+	b := want.Bounds()
+	img := render(t, b.Dx()+2, b.Dy()+2, func(c ui.Canvas) {
+		if err := c.DrawPNGErr(gopherPNG, 1, 1); err != nil {
+			t.Errorf("DrawPNGErr: %v", err)
+		}
+	})
+	// Compare the fully opaque pixels, which are unaffected by blending.
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			if c := want.NRGBAAt(x, y); c.A == 255 {
+				checkPixels(t, img, c, image.Pt(x-b.Min.X+1, y-b.Min.Y+1))
+			}
+		}
+	}
+	checkPixels(t, img, black, image.Pt(0, 0))
+}
+
+func TestDrawPNGMissing(t *testing.T) {
+	render(t, 4, 4, func(c ui.Canvas) {
+		err := c.DrawPNGErr("resrc/missing.png", 0, 0)
+		var lerr *ui.LoadError
+		if !errors.As(err, &lerr) || lerr.Path != "resrc/missing.png" {
+			t.Errorf("DrawPNGErr of a missing file: got %v, want a *LoadError", err)
+		}
+	})
+}
+
+func TestFillString(t *testing.T) {
+	var sw, sh, fw, fh int
+	img := render(t, 64, 32, func(c ui.Canvas) {
+		if err := c.SetFontErr(fontTTF, 8); err != nil {
+			t.Errorf("SetFontErr: %v", err)
+			return
+		}
+		c.SetColor(white)
+		sw, sh = c.StringSize("Hi")
+		var err error
+		if fw, fh, err = c.FillStringErr("Hi", 2, 2); err != nil {
+			t.Errorf("FillStringErr: %v", err)
+		}
+	})
+	if sw <= 0 || sh <= 0 {
+		t.Fatalf("StringSize = %d, %d, want positive", sw, sh)
+	}
+	if fw != sw || fh != sh {
+		t.Errorf("FillStringErr = %d, %d, want StringSize %d, %d", fw, fh, sw, sh)
+	}
+	inside, outside := 0, 0
+	for y := 0; y < 32; y++ {
+		for x := 0; x < 64; x++ {
+			if img.NRGBAAt(x, y) == black {
+				continue
+			}
+			if image.Pt(x, y).In(image.Rect(2, 2, 2+fw, 2+fh)) {
+				inside++
+			} else {
+				outside++
+			}
+		}
+	}
+	if inside == 0 {
+		t.Errorf("FillStringErr drew nothing")
+	}
+	if outside != 0 {
+		t.Errorf("FillStringErr drew %d pixels outside of its bounding box", outside)
+	}
+}
+
+func TestFillStringColor(t *testing.T) {
+	img := render(t, 32, 16, func(c ui.Canvas) {
+		if err := c.SetFontErr(fontTTF, 8); err != nil {
+			t.Errorf("SetFontErr: %v", err)
+			return
+		}
+		c.SetColor(red)
+		c.FillString("ui", 2, 2)
+	})
+	n := 0
+	for y := 0; y < 16; y++ {
+		for x := 0; x < 32; x++ {
+			if c := img.NRGBAAt(x, y); c != black {
+				n++
+				// Antialiased edges are red blended with the black background.
+				if c.G != 0 || c.B != 0 {
+					t.Errorf("pixel %d, %d: got %v, want a shade of red", x, y, c)
+				}
+			}
+		}
+	}
+	if n == 0 {
+		t.Errorf("FillString drew nothing")
+	}
+}
+
+func TestFillStringNoFont(t *testing.T) {
+	render(t, 4, 4, func(c ui.Canvas) {
+		if _, _, err := c.FillStringErr("Hi", 0, 0); err != ui.ErrNoFont {
+			t.Errorf("FillStringErr without a font: got %v, want %v", err, ui.ErrNoFont)
+		}
+	})
+}
+
+func TestSetFontMissing(t *testing.T) {
+	render(t, 4, 4, func(c ui.Canvas) {
+		var lerr *ui.LoadError
+		if err := c.SetFontErr("resrc/missing.ttf", 8); !errors.As(err, &lerr) {
+			t.Errorf("SetFontErr of a missing file: got %v, want a *LoadError", err)
+		}
+	})
+}
+
+func TestReadPixelsRect(t *testing.T) {
+	render(t, 8, 8, func(c ui.Canvas) {
+		c.SetColor(white)
+		c.FillRects(image.Rect(2, 2, 4, 4))
+		r := image.Rect(2, 2, 6, 10)
+		img, err := c.ReadPixels(r)
+		if err != nil {
+			t.Errorf("ReadPixels: %v", err)
+			return
+		}
+		if want := image.Rect(2, 2, 6, 8); img.Bounds() != want {
+			t.Errorf("ReadPixels(%v).Bounds() = %v, want %v", r, img.Bounds(), want)
+		}
+		checkPixels(t, img, white, image.Pt(2, 2), image.Pt(3, 3))
+		checkPixels(t, img, black, image.Pt(4, 4), image.Pt(5, 7))
+	})
+}
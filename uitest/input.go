@@ -0,0 +1,67 @@
+// © 2013 the Ui Authors under the MIT license. See AUTHORS for the list of authors.
+
+package uitest
+
+import (
+	"time"
+	"unicode/utf8"
+
+	"github.com/velour/ui"
+)
+
+// Click injects a press and release of the mouse button at x, y in the window.
+// The mouse is first moved to x, y.
+func Click(win *ui.Window, b ui.Button, x, y int) error {
+	evs := []ui.Event{
+		&ui.MouseMotionEvent{X: x, Y: y},
+		&ui.MouseButtonEvent{Button: b, Down: true, X: x, Y: y},
+		&ui.MouseButtonEvent{Button: b, Down: false, X: x, Y: y},
+	}
+	return inject(win, evs)
+}
+
+// Type injects a TextInputEvent into the window for each rune of s,
+// as if s were typed while text input is started.
+func Type(win *ui.Window, s string) error {
+	var evs []ui.Event
+	for len(s) > 0 {
+		_, n := utf8.DecodeRuneInString(s)
+		evs = append(evs, &ui.TextInputEvent{Text: s[:n]})
+		s = s[n:]
+	}
+	return inject(win, evs)
+}
+
+// Key injects a press and release of the key, with the modifiers held, into the window.
+func Key(win *ui.Window, k ui.Key, mod ui.KeyMod) error {
+	evs := []ui.Event{
+		&ui.KeyboardEvent{Key: k, Mod: mod, Down: true},
+		&ui.KeyboardEvent{Key: k, Mod: mod, Down: false},
+	}
+	return inject(win, evs)
+}
+
+func inject(win *ui.Window, evs []ui.Event) error {
+	for _, ev := range evs {
+		if err := win.Inject(ev); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Next returns the next event delivered to the window, or nil if none
+// arrives within timeout or the window is destroyed.
+func Next(win *ui.Window, timeout time.Duration) ui.Event {
+	t := time.NewTimer(timeout)
+	defer t.Stop()
+	select {
+	case ev, ok := <-win.Events():
+		if !ok {
+			return nil
+		}
+		return ev
+	case <-t.C:
+		return nil
+	}
+}
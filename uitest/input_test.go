@@ -0,0 +1,80 @@
+// © 2013 the Ui Authors under the MIT license. See AUTHORS for the list of authors.
+
+package uitest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/velour/ui"
+)
+
+// NextInput returns the next event delivered to the window that is not a WindowEvent,
+// such as those sent by SDL when the window is shown, or nil if none arrives within a second.
+func nextInput(win *ui.Window) ui.Event {
+	for {
+		ev := Next(win, time.Second)
+		if _, ok := ev.(*ui.WindowEvent); !ok {
+			return ev
+		}
+	}
+}
+
+func newWindow(t *testing.T) *ui.Window {
+	t.Helper()
+	win, err := ui.NewWindowErr("test", 32, 32)
+	if err != nil {
+		t.Fatalf("NewWindowErr: %v", err)
+	}
+	return win
+}
+
+func TestClick(t *testing.T) {
+	win := newWindow(t)
+	defer win.Destroy()
+	if err := Click(win, ui.ButtonRight, 5, 7); err != nil {
+		t.Fatalf("Click: %v", err)
+	}
+
+	m, ok := nextInput(win).(*ui.MouseMotionEvent)
+	if !ok || m.X != 5 || m.Y != 7 {
+		t.Errorf("first event = %v, want a MouseMotionEvent to 5, 7", m)
+	}
+	for _, down := range []bool{true, false} {
+		ev := nextInput(win)
+		b, ok := ev.(*ui.MouseButtonEvent)
+		if !ok || b.Button != ui.ButtonRight || b.Down != down || b.Clicks != 1 || b.X != 5 || b.Y != 7 {
+			t.Errorf("got %v, want a single click of the right button at 5, 7 with Down: %t", ev, down)
+		}
+	}
+}
+
+func TestType(t *testing.T) {
+	win := newWindow(t)
+	defer win.Destroy()
+	want := []string{"a", "é", "世", "😀"}
+	if err := Type(win, "aé世😀"); err != nil {
+		t.Fatalf("Type: %v", err)
+	}
+	for _, w := range want {
+		ev := nextInput(win)
+		if e, ok := ev.(*ui.TextInputEvent); !ok || e.Text != w {
+			t.Errorf("got %v, want TextInputEvent{Text: %q}", ev, w)
+		}
+	}
+}
+
+func TestKey(t *testing.T) {
+	win := newWindow(t)
+	defer win.Destroy()
+	if err := Key(win, ui.KeyEscape, ui.ModLShift); err != nil {
+		t.Fatalf("Key: %v", err)
+	}
+	for _, down := range []bool{true, false} {
+		ev := nextInput(win)
+		k, ok := ev.(*ui.KeyboardEvent)
+		if !ok || k.Key != ui.KeyEscape || k.Scancode != ui.ScancodeEscape || k.Mod != ui.ModLShift || k.Down != down {
+			t.Errorf("got %v, want Escape with Shift and Down: %t", ev, down)
+		}
+	}
+}
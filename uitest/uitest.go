@@ -0,0 +1,166 @@
+// © 2013 the Ui Authors under the MIT license. See AUTHORS for the list of authors.
+
+// Package uitest provides support for testing programs that use package ui.
+//
+// Tests using uitest run against a headless user interface, so they need
+// no display or GPU.  The user interface must be run by the test binary's
+// main go routine, so a package's tests must call Main from TestMain:
+//
+//	func TestMain(m *testing.M) {
+//		uitest.Main(m)
+//	}
+//
+// Drawing can then be checked against golden images stored in the package's
+// testdata directory:
+//
+//	func TestButton(t *testing.T) {
+//		uitest.CheckDraw(t, "button", 64, 32, 0, func(c ui.Canvas) {
+//			drawButton(c)
+//		})
+//	}
+//
+// Running the tests with the -uitest.update flag regenerates the golden images.
+//
+// Event handling can be tested by injecting input into a window with Click,
+// Type, and Key, which go through SDL's event queue like real input:
+//
+//	func TestEscape(t *testing.T) {
+//		win, err := ui.NewWindowErr("test", 64, 64)
+//		...
+//		uitest.Key(win, ui.KeyEscape, ui.ModNone)
+//		ev, _ := uitest.Next(win, time.Second).(*ui.KeyboardEvent)
+//		if ev == nil || ev.Key != ui.KeyEscape {
+//			t.Errorf("got %v, want KeyEscape", ev)
+//		}
+//	}
+package uitest
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"image"
+	"image/color"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/velour/ui"
+)
+
+var update = flag.Bool("uitest.update", false, "regenerate golden images instead of comparing against them")
+
+// Main runs the tests in m with a headless user interface, and then exits.
+// It must be called from TestMain.
+func Main(m *testing.M) {
+	code := 0
+	err := ui.RunWithOptions(context.Background(), func(context.Context) error {
+		code = m.Run()
+		return nil
+	}, ui.Options{Headless: true})
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "uitest:", err)
+		os.Exit(1)
+	}
+	os.Exit(code)
+}
+
+// Render draws with f on a new canvas of the given width and height, and returns the
+// drawn pixels.  The canvas is cleared to opaque black before f is called.
+func Render(width, height int, f func(ui.Canvas)) (*image.NRGBA, error) {
+	win, err := ui.NewWindowErr("uitest", width, height)
+	if err != nil {
+		return nil, err
+	}
+	defer win.Destroy()
+
+	var img *image.NRGBA
+	var readErr error
+	err = win.Draw(func(c ui.Canvas) {
+		c.SetColor(color.Black)
+		c.Clear()
+		f(c)
+		img, readErr = c.ReadPixels(image.ZR)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return img, readErr
+}
+
+// CheckDraw renders f as with Render and compares the result to the named
+// golden image as with Golden.
+func CheckDraw(t testing.TB, name string, width, height int, tolerance uint8, f func(ui.Canvas)) {
+	t.Helper()
+	img, err := Render(width, height, f)
+	if err != nil {
+		t.Fatalf("render %s: %v", name, err)
+	}
+	Golden(t, name, img, tolerance)
+}
+
+// Golden compares img to the golden image testdata/name.png, and reports a test
+// failure if their sizes differ or if any color channel of any pixel differs by more
+// than tolerance.  On failure, img is written to testdata/name.got.png for inspection.
+//
+// If the -uitest.update flag is set, img is written as the new golden image instead.
+func Golden(t testing.TB, name string, img image.Image, tolerance uint8) {
+	t.Helper()
+	path := filepath.Join("testdata", name+".png")
+	if *update {
+		if err := os.MkdirAll("testdata", 0777); err != nil {
+			t.Fatal(err)
+		}
+		if err := ui.SavePNG(path, img); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+
+	want, err := ui.LoadImage(path)
+	if err != nil {
+		t.Fatalf("%v (run with -uitest.update to create it)", err)
+	}
+	n, err := Diff(img, want, tolerance)
+	if err == nil && n == 0 {
+		return
+	}
+	got := filepath.Join("testdata", name+".got.png")
+	if err := ui.SavePNG(got, img); err != nil {
+		t.Errorf("saving %s: %v", got, err)
+	}
+	if err != nil {
+		t.Errorf("%s: %v; got image saved to %s", path, err, got)
+	} else {
+		t.Errorf("%s: %d pixels differ by more than %d; got image saved to %s", path, n, tolerance, got)
+	}
+}
+
+// Diff returns the number of pixels of a and b that have a color channel differing by
+// more than tolerance.  Colors are compared non-alpha-premultiplied, with 8 bits per channel.
+// Diff returns an error if the images differ in size.
+func Diff(a, b image.Image, tolerance uint8) (int, error) {
+	ab, bb := a.Bounds(), b.Bounds()
+	if ab.Size() != bb.Size() {
+		return 0, fmt.Errorf("size %v does not match %v", ab.Size(), bb.Size())
+	}
+	n := 0
+	for y := 0; y < ab.Dy(); y++ {
+		for x := 0; x < ab.Dx(); x++ {
+			ac := color.NRGBAModel.Convert(a.At(ab.Min.X+x, ab.Min.Y+y)).(color.NRGBA)
+			bc := color.NRGBAModel.Convert(b.At(bb.Min.X+x, bb.Min.Y+y)).(color.NRGBA)
+			if differ(ac.R, bc.R, tolerance) || differ(ac.G, bc.G, tolerance) ||
+				differ(ac.B, bc.B, tolerance) || differ(ac.A, bc.A, tolerance) {
+				n++
+			}
+		}
+	}
+	return n, nil
+}
+
+func differ(a, b, tolerance uint8) bool {
+	if a > b {
+		return a-b > tolerance
+	}
+	return b-a > tolerance
+}
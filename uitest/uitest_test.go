@@ -0,0 +1,193 @@
+// © 2013 the Ui Authors under the MIT license. See AUTHORS for the list of authors.
+
+package uitest
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/velour/ui"
+)
+
+func TestMain(m *testing.M) {
+	Main(m)
+}
+
+func uniform(w, h int, c color.NRGBA) *image.NRGBA {
+	img := image.NewNRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			img.SetNRGBA(x, y, c)
+		}
+	}
+	return img
+}
+
+func TestDiff(t *testing.T) {
+	a := uniform(4, 4, color.NRGBA{100, 100, 100, 255})
+	b := uniform(4, 4, color.NRGBA{100, 100, 100, 255})
+	b.SetNRGBA(1, 1, color.NRGBA{102, 100, 100, 255})
+	b.SetNRGBA(2, 2, color.NRGBA{100, 100, 100, 250})
+
+	tests := []struct {
+		tolerance uint8
+		want      int
+	}{
+		{0, 2},
+		{1, 2},
+		{2, 1},
+		{5, 0},
+	}
+	for _, test := range tests {
+		n, err := Diff(a, b, test.tolerance)
+		if err != nil || n != test.want {
+			t.Errorf("Diff(tolerance=%d) = %d, %v, want %d, nil", test.tolerance, n, err, test.want)
+		}
+	}
+}
+
+func TestDiffBounds(t *testing.T) {
+	a := uniform(4, 4, color.NRGBA{1, 2, 3, 255})
+	b := image.NewNRGBA(image.Rect(10, 10, 14, 14))
+	for y := 10; y < 14; y++ {
+		for x := 10; x < 14; x++ {
+			b.SetNRGBA(x, y, color.NRGBA{1, 2, 3, 255})
+		}
+	}
+	if n, err := Diff(a, b, 0); err != nil || n != 0 {
+		t.Errorf("Diff with offset bounds = %d, %v, want 0, nil", n, err)
+	}
+}
+
+func TestDiffSize(t *testing.T) {
+	a := uniform(4, 4, color.NRGBA{})
+	b := uniform(4, 5, color.NRGBA{})
+	if _, err := Diff(a, b, 255); err == nil {
+		t.Errorf("Diff of different sizes succeeded")
+	}
+}
+
+// A fakeTB records the failures reported to it.
+type fakeTB struct {
+	testing.TB
+	errors []string
+}
+
+// FatalError is panicked by fakeTB.Fatalf to stop the function under test.
+type fatalError string
+
+func (f *fakeTB) Helper() {}
+
+func (f *fakeTB) Errorf(format string, args ...interface{}) {
+	f.errors = append(f.errors, fmt.Sprintf(format, args...))
+}
+
+func (f *fakeTB) Fatal(args ...interface{}) {
+	panic(fatalError(fmt.Sprint(args...)))
+}
+
+func (f *fakeTB) Fatalf(format string, args ...interface{}) {
+	panic(fatalError(fmt.Sprintf(format, args...)))
+}
+
+// Golden calls Golden with a fakeTB, and returns its errors and fatal error, if any.
+func golden(name string, img image.Image, tolerance uint8) (errs []string, fatal string) {
+	tb := &fakeTB{}
+	defer func() {
+		if r := recover(); r != nil {
+			f, ok := r.(fatalError)
+			if !ok {
+				panic(r)
+			}
+			errs, fatal = tb.errors, string(f)
+		}
+	}()
+	Golden(tb, name, img, tolerance)
+	return tb.errors, ""
+}
+
+// Chtemp changes to a new temporary directory, and returns a function that
+// changes back and removes it.
+func chtemp(t *testing.T) func() {
+	dir, err := ioutil.TempDir("", "uitest")
+	if err != nil {
+		t.Fatal(err)
+	}
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	return func() {
+		os.Chdir(wd)
+		os.RemoveAll(dir)
+	}
+}
+
+func TestGolden(t *testing.T) {
+	defer chtemp(t)()
+
+	want := uniform(4, 4, color.NRGBA{10, 20, 30, 255})
+	if _, fatal := golden("img", want, 0); fatal == "" {
+		t.Errorf("Golden with a missing golden image did not fail")
+	}
+
+	*update = true
+	errs, fatal := golden("img", want, 0)
+	*update = false
+	if len(errs) != 0 || fatal != "" {
+		t.Fatalf("Golden with -uitest.update: %v %s", errs, fatal)
+	}
+	if _, err := os.Stat(filepath.Join("testdata", "img.png")); err != nil {
+		t.Fatalf("Golden with -uitest.update did not write the golden image: %v", err)
+	}
+
+	if errs, fatal := golden("img", want, 0); len(errs) != 0 || fatal != "" {
+		t.Errorf("Golden of a matching image: %v %s", errs, fatal)
+	}
+
+	got := uniform(4, 4, color.NRGBA{12, 20, 30, 255})
+	if errs, fatal := golden("img", got, 2); len(errs) != 0 || fatal != "" {
+		t.Errorf("Golden within tolerance: %v %s", errs, fatal)
+	}
+	if errs, _ := golden("img", got, 1); len(errs) != 1 {
+		t.Errorf("Golden beyond tolerance: got %d errors, want 1", len(errs))
+	}
+	saved, err := ui.LoadImage(filepath.Join("testdata", "img.got.png"))
+	if err != nil {
+		t.Fatalf("Golden did not save the failing image: %v", err)
+	}
+	if n, err := Diff(saved, got, 0); err != nil || n != 0 {
+		t.Errorf("saved image differs from the failing image: %d, %v", n, err)
+	}
+
+	if errs, _ := golden("img", uniform(4, 3, color.NRGBA{10, 20, 30, 255}), 255); len(errs) != 1 {
+		t.Errorf("Golden of a different size: got %d errors, want 1", len(errs))
+	}
+}
+
+func TestRender(t *testing.T) {
+	img, err := Render(8, 4, func(c ui.Canvas) {
+		c.SetColor(color.White)
+		c.FillRects(image.Rect(0, 0, 4, 4))
+	})
+	if err != nil {
+		t.Fatalf("Render: %v", err)
+	}
+	if img.Bounds() != image.Rect(0, 0, 8, 4) {
+		t.Fatalf("Render bounds = %v, want 8x4", img.Bounds())
+	}
+	if c := img.NRGBAAt(1, 1); c != (color.NRGBA{255, 255, 255, 255}) {
+		t.Errorf("filled pixel = %v, want white", c)
+	}
+	if c := img.NRGBAAt(6, 1); c != (color.NRGBA{0, 0, 0, 255}) {
+		t.Errorf("cleared pixel = %v, want opaque black", c)
+	}
+}
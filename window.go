@@ -0,0 +1,211 @@
+// © 2013 the Ui Authors under the MIT license. See AUTHORS for the list of authors.
+
+package ui
+
+/*
+#include "ui.h"
+*/
+import "C"
+
+import (
+	"image"
+	"unsafe"
+)
+
+// ID returns the window's identifier, which is unique among the windows
+// of a run of the user interface.
+func (win *Window) ID() uint32 {
+	return uint32(win.id)
+}
+
+// SetTitle sets the window's title.
+func (win *Window) SetTitle(title string) error {
+	return win.do(func() error {
+		ctitle := C.CString(title)
+		defer C.free(unsafe.Pointer(ctitle))
+		C.SDL_SetWindowTitle(win.win, ctitle)
+		return nil
+	})
+}
+
+// Size returns the size of the window's client area, in screen coordinates.
+// On high-DPI displays, this may differ from the canvas size in pixels.
+func (win *Window) Size() (width, height int) {
+	win.do(func() error {
+		var w, h C.int
+		C.SDL_GetWindowSize(win.win, &w, &h)
+		width, height = int(w), int(h)
+		return nil
+	})
+	return width, height
+}
+
+// SetSize sets the size of the window's client area, in screen coordinates.
+func (win *Window) SetSize(width, height int) error {
+	return win.do(func() error {
+		C.SDL_SetWindowSize(win.win, C.int(width), C.int(height))
+		return nil
+	})
+}
+
+// Position returns the position of the window's upper-left corner, in screen coordinates.
+func (win *Window) Position() (x, y int) {
+	win.do(func() error {
+		var cx, cy C.int
+		C.SDL_GetWindowPosition(win.win, &cx, &cy)
+		x, y = int(cx), int(cy)
+		return nil
+	})
+	return x, y
+}
+
+// SetPosition sets the position of the window's upper-left corner, in screen coordinates.
+func (win *Window) SetPosition(x, y int) error {
+	return win.do(func() error {
+		C.SDL_SetWindowPosition(win.win, C.int(x), C.int(y))
+		return nil
+	})
+}
+
+// Show shows the window.
+func (win *Window) Show() error {
+	return win.do(func() error {
+		C.SDL_ShowWindow(win.win)
+		return nil
+	})
+}
+
+// Hide hides the window.
+func (win *Window) Hide() error {
+	return win.do(func() error {
+		C.SDL_HideWindow(win.win)
+		return nil
+	})
+}
+
+// Raise raises the window above other windows and gives it input focus.
+func (win *Window) Raise() error {
+	return win.do(func() error {
+		C.SDL_RaiseWindow(win.win)
+		return nil
+	})
+}
+
+// Minimize minimizes the window.
+func (win *Window) Minimize() error {
+	return win.do(func() error {
+		C.SDL_MinimizeWindow(win.win)
+		return nil
+	})
+}
+
+// Maximize maximizes the window.
+func (win *Window) Maximize() error {
+	return win.do(func() error {
+		C.SDL_MaximizeWindow(win.win)
+		return nil
+	})
+}
+
+// Restore restores a minimized or maximized window to its normal size and position.
+func (win *Window) Restore() error {
+	return win.do(func() error {
+		C.SDL_RestoreWindow(win.win)
+		return nil
+	})
+}
+
+// A FullscreenMode is a mode for SetFullscreen.
+type FullscreenMode int
+
+const (
+	// Windowed is a normal, non-fullscreen window.
+	Windowed FullscreenMode = iota
+
+	// Fullscreen is a fullscreen window that changes the display mode to the window's size.
+	Fullscreen
+
+	// FullscreenDesktop is a fullscreen window at the desktop's resolution.
+	FullscreenDesktop
+)
+
+// SetFullscreen sets the window's fullscreen mode.
+func (win *Window) SetFullscreen(mode FullscreenMode) error {
+	var flags C.Uint32
+	switch mode {
+	case Fullscreen:
+		flags = C.SDL_WINDOW_FULLSCREEN
+	case FullscreenDesktop:
+		flags = C.SDL_WINDOW_FULLSCREEN_DESKTOP
+	}
+	return win.do(func() error {
+		if C.SDL_SetWindowFullscreen(win.win, flags) < 0 {
+			return sdlError("SDL_SetWindowFullscreen")
+		}
+		return nil
+	})
+}
+
+// SetOpacity sets the window's opacity, from 0 (transparent) to 1 (opaque).
+// It returns an error if the system does not support window opacity.
+func (win *Window) SetOpacity(opacity float64) error {
+	return win.do(func() error {
+		if C.SDL_SetWindowOpacity(win.win, C.float(opacity)) < 0 {
+			return sdlError("SDL_SetWindowOpacity")
+		}
+		return nil
+	})
+}
+
+// SetIcon sets the window's icon.
+func (win *Window) SetIcon(img image.Image) error {
+	nrgba := toNRGBA(img)
+	b := nrgba.Bounds()
+	if b.Empty() {
+		return nil
+	}
+	return win.do(func() error {
+		// The surface refers to its pixels, so they must be in C memory.
+		pix := C.CBytes(nrgba.Pix)
+		defer C.free(pix)
+		// The masks describe NRGBA byte order on a little-endian machine,
+		// like SDL_PIXELFORMAT_ABGR8888 used for textures.
+		surf := C.SDL_CreateRGBSurfaceFrom(pix, C.int(b.Dx()), C.int(b.Dy()), 32, C.int(nrgba.Stride),
+			0x000000FF, 0x0000FF00, 0x00FF0000, 0xFF000000)
+		if surf == nil {
+			return sdlError("SDL_CreateRGBSurfaceFrom")
+		}
+		defer C.SDL_FreeSurface(surf)
+		C.SDL_SetWindowIcon(win.win, surf)
+		return nil
+	})
+}
+
+// StartTextInput starts delivering TextInputEvents and TextEditingEvents,
+// and shows the on-screen keyboard or input method, if any.
+//
+// Text input is not specific to a window: SDL delivers text to whichever
+// window has keyboard focus.
+func (win *Window) StartTextInput() error {
+	return win.do(func() error {
+		C.SDL_StartTextInput()
+		return nil
+	})
+}
+
+// StopTextInput stops delivering TextInputEvents and TextEditingEvents.
+func (win *Window) StopTextInput() error {
+	return win.do(func() error {
+		C.SDL_StopTextInput()
+		return nil
+	})
+}
+
+// SetTextInputRect sets the rectangle, in window coordinates, of the text being
+// edited, so that an input method can place its candidate list near the caret.
+func (win *Window) SetTextInputRect(r image.Rectangle) error {
+	return win.do(func() error {
+		C.SDL_SetTextInputRect(sdlRect(&r))
+		return nil
+	})
+}